@@ -0,0 +1,86 @@
+package fpdecimal
+
+import (
+	"cmp"
+	"math/bits"
+	"slices"
+)
+
+// Allocate distributes v by ratios in multiples of unit, parts sum exactly to v and have same sign as v.
+// Parts are truncated proportional shares, units left go one each to parts of largest remainders, ties to first parts.
+// This is largest remainder method. Fractions of unit go to first part of largest ratio.
+// Panics when unit is not positive, or ratios are negative or sum to zero.
+func Allocate(v, unit int64, ratios []int64) []int64 {
+	if unit <= 0 {
+		panic("unit of allocation is not positive")
+	}
+
+	parts := allocate(abs(v), uint64(unit), ratios)
+	vs := make([]int64, len(parts))
+	for i, p := range parts {
+		if v < 0 {
+			vs[i] = int64(-p)
+		} else {
+			vs[i] = int64(p)
+		}
+	}
+	return vs
+}
+
+// AllocateUint is Allocate for uint64.
+func AllocateUint(v, unit uint64, ratios []int64) []uint64 {
+	if unit == 0 {
+		panic("unit of allocation is not positive")
+	}
+	return allocate(v, unit, ratios)
+}
+
+// allocate distributes magnitude m in multiples of unit u.
+func allocate(m, u uint64, ratios []int64) []uint64 {
+	var total, carry uint64
+	for _, r := range ratios {
+		if r < 0 {
+			panic("ratio of allocation is negative")
+		}
+		if total, carry = bits.Add64(total, uint64(r), 0); carry != 0 {
+			panic("sum of ratios overflows")
+		}
+	}
+	if total == 0 {
+		panic("sum of ratios is zero")
+	}
+
+	k, rest := m/u, m%u
+
+	// each ratio is at most total, so high bits of product are less than total
+	parts := make([]uint64, len(ratios))
+	rems := make([]uint64, len(ratios))
+	left, largest := k, 0
+	for i, r := range ratios {
+		hi, lo := bits.Mul64(k, uint64(r))
+		parts[i], rems[i] = bits.Div64(hi, lo, total)
+		left -= parts[i]
+		if r > ratios[largest] {
+			largest = i
+		}
+	}
+
+	if left > 0 {
+		order := make([]int, len(ratios))
+		for i := range order {
+			order[i] = i
+		}
+		slices.SortStableFunc(order, func(a, b int) int { return cmp.Compare(rems[b], rems[a]) })
+		for _, i := range order[:left] {
+			parts[i]++
+		}
+	}
+
+	for i := range parts {
+		parts[i] *= u
+		if i == largest {
+			parts[i] += rest
+		}
+	}
+	return parts
+}
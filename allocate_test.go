@@ -0,0 +1,150 @@
+package fpdecimal_test
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"slices"
+	"testing"
+
+	"github.com/nikolaydubina/fpdecimal"
+)
+
+func TestAllocate(t *testing.T) {
+	tests := []struct {
+		v, unit int64
+		ratios  []int64
+		vs      []int64
+	}{
+		{0, 1, []int64{1, 1}, []int64{0, 0}},
+		{100, 1, []int64{1, 1, 1}, []int64{34, 33, 33}},
+		{-100, 1, []int64{1, 1, 1}, []int64{-34, -33, -33}},
+		{100_000, 10, []int64{1, 1, 1}, []int64{33_340, 33_330, 33_330}},
+		{100_005, 10, []int64{1, 2, 1}, []int64{25_000, 50_005, 25_000}},
+		{5, 1, []int64{1, 0, 3}, []int64{1, 0, 4}},
+		{10, 1, []int64{7}, []int64{10}},
+		{1, 1, []int64{1, 1, 1}, []int64{1, 0, 0}},
+		{2, 1, []int64{1, 2, 2}, []int64{0, 1, 1}},
+		{7, 1, []int64{30, 20, 50}, []int64{2, 1, 4}},
+		{math.MaxInt64, 1, []int64{1, 1}, []int64{math.MaxInt64/2 + 1, math.MaxInt64 / 2}},
+		{math.MinInt64, 1, []int64{1, 1}, []int64{math.MinInt64 / 2, math.MinInt64 / 2}},
+		{math.MaxInt64, 1, []int64{math.MaxInt64, math.MaxInt64}, []int64{math.MaxInt64/2 + 1, math.MaxInt64 / 2}},
+	}
+	for _, tc := range tests {
+		t.Run(fmt.Sprint(tc.v, tc.unit, tc.ratios), func(t *testing.T) {
+			if vs := fpdecimal.Allocate(tc.v, tc.unit, tc.ratios); !slices.Equal(vs, tc.vs) {
+				t.Error(vs, tc.vs)
+			}
+		})
+	}
+}
+
+func TestAllocateUint(t *testing.T) {
+	tests := []struct {
+		v, unit uint64
+		ratios  []int64
+		vs      []uint64
+	}{
+		{0, 1, []int64{1, 1}, []uint64{0, 0}},
+		{100, 1, []int64{1, 1, 1}, []uint64{34, 33, 33}},
+		{100_005, 10, []int64{1, 2, 1}, []uint64{25_000, 50_005, 25_000}},
+		{math.MaxUint64, 1, []int64{1, 1}, []uint64{math.MaxUint64/2 + 1, math.MaxUint64 / 2}},
+		{math.MaxUint64, math.MaxUint64, []int64{1, 1}, []uint64{math.MaxUint64, 0}},
+	}
+	for _, tc := range tests {
+		t.Run(fmt.Sprint(tc.v, tc.unit, tc.ratios), func(t *testing.T) {
+			if vs := fpdecimal.AllocateUint(tc.v, tc.unit, tc.ratios); !slices.Equal(vs, tc.vs) {
+				t.Error(vs, tc.vs)
+			}
+		})
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("no panic")
+		}
+	}()
+	fpdecimal.AllocateUint(1, 0, []int64{1})
+}
+
+func TestAllocate_Panic(t *testing.T) {
+	tests := []struct {
+		unit   int64
+		ratios []int64
+	}{
+		{0, []int64{1}},
+		{-1, []int64{1}},
+		{1, nil},
+		{1, []int64{0, 0}},
+		{1, []int64{1, -1}},
+		{1, []int64{math.MaxInt64, math.MaxInt64, math.MaxInt64}},
+	}
+	for _, tc := range tests {
+		t.Run(fmt.Sprint(tc.unit, tc.ratios), func(t *testing.T) {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Error("no panic")
+				}
+			}()
+			fpdecimal.Allocate(100, tc.unit, tc.ratios)
+		})
+	}
+}
+
+func FuzzAllocate(f *testing.F) {
+	tests := []struct {
+		v, unit    int64
+		r1, r2, r3 int64
+	}{
+		{100, 1, 1, 1, 1},
+		{100_005, 10, 1, 2, 1},
+		{7, 1, 30, 20, 50},
+		{math.MinInt64, 3, 1, 0, math.MaxInt64},
+	}
+	for _, tc := range tests {
+		f.Add(tc.v, tc.unit, tc.r1, tc.r2, tc.r3)
+		f.Add(-tc.v, tc.unit, tc.r1, tc.r2, tc.r3)
+	}
+	f.Fuzz(func(t *testing.T, v, unit, r1, r2, r3 int64) {
+		ratios := []int64{r1, r2, r3}
+		if unit <= 0 || r1 < 0 || r2 < 0 || r3 < 0 || r1+r2+r3 <= 0 || r1+r2 < 0 {
+			t.Skip()
+		}
+
+		vs := fpdecimal.Allocate(v, unit, ratios)
+
+		sum := new(big.Int)
+		for _, p := range vs {
+			sum.Add(sum, big.NewInt(p))
+		}
+		if sum.Cmp(big.NewInt(v)) != 0 {
+			t.Error(v, unit, ratios, vs)
+		}
+
+		// parts differ from exact shares of units by less than one unit, not counting fractions of unit
+		k := new(big.Int).Quo(big.NewInt(v), big.NewInt(unit))
+		total := big.NewInt(r1 + r2 + r3)
+		for i, p := range vs {
+			if (p < 0 && v > 0) || (p > 0 && v < 0) {
+				t.Error(v, unit, ratios, vs)
+			}
+
+			share := new(big.Rat).SetFrac(new(big.Int).Mul(k, big.NewInt(ratios[i])), total)
+			diff := new(big.Rat).Sub(new(big.Rat).SetFrac(big.NewInt(p/unit), big.NewInt(1)), share)
+			if diff.Abs(diff).Cmp(big.NewRat(1, 1)) >= 0 {
+				t.Error(v, unit, ratios, vs, i)
+			}
+		}
+	})
+}
+
+func BenchmarkAllocate(b *testing.B) {
+	ratios := []int64{1, 1, 1}
+	var vs []int64
+	for n := 0; n < b.N; n++ {
+		vs = fpdecimal.Allocate(100_000, 10, ratios)
+	}
+	if vs[0] != 33_340 {
+		b.Error(vs)
+	}
+}
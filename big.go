@@ -0,0 +1,98 @@
+package fpdecimal
+
+import (
+	"encoding/binary"
+	"math/big"
+)
+
+// BigRatToFixedPointDecimal converts rational into fixed-point decimal of p fractions, rounded by mode.
+func BigRatToFixedPointDecimal(r *big.Rat, p uint8, mode RoundingMode) (int64, error) {
+	q := bigRatRound(r, p, mode)
+	if !q.IsInt64() {
+		return 0, errOverflow
+	}
+	return q.Int64(), nil
+}
+
+// BigRatToFixedPointDecimalUint is BigRatToFixedPointDecimal for uint64, negative value is error.
+func BigRatToFixedPointDecimalUint(r *big.Rat, p uint8, mode RoundingMode) (uint64, error) {
+	q := bigRatRound(r, p, mode)
+	if q.Sign() < 0 {
+		return 0, errNegative
+	}
+	if !q.IsUint64() {
+		return 0, errOverflow
+	}
+	return q.Uint64(), nil
+}
+
+// BigRatToFixedPointDecimalWide is BigRatToFixedPointDecimal for two's complement of high and low bits.
+func BigRatToFixedPointDecimalWide(r *big.Rat, p uint8, mode RoundingMode) (hi int64, lo uint64, err error) {
+	q := bigRatRound(r, p, mode)
+	if q.BitLen() > 128 {
+		return 0, 0, errOverflow
+	}
+	var b [16]byte
+	q.FillBytes(b[:])
+	return signedWide(binary.BigEndian.Uint64(b[:8]), binary.BigEndian.Uint64(b[8:]), q.Sign() < 0)
+}
+
+// bigRatRound returns rational scaled to p fractions, rounded by mode.
+func bigRatRound(r *big.Rat, p uint8, mode RoundingMode) *big.Int {
+	q := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(p)), nil)
+	q.Mul(q, r.Num())
+
+	m := new(big.Int)
+	q.QuoRem(q, r.Denom(), m)
+
+	if m.Sign() != 0 {
+		neg := r.Sign() < 0
+
+		// only comparison of remainder to half of divisor matters, so it is kept by small numbers
+		var rem, d uint64 = 1, 3
+		switch m.Abs(m).Lsh(m, 1).Cmp(r.Denom()) {
+		case 0:
+			rem, d = 1, 2
+		case 1:
+			rem, d = 2, 3
+		}
+
+		if mode.roundUp(uint64(q.Bit(0)), rem, d, neg) {
+			if neg {
+				q.Sub(q, big.NewInt(1))
+			} else {
+				q.Add(q, big.NewInt(1))
+			}
+		}
+	}
+
+	return q
+}
+
+// BigFloatToFixedPointDecimal converts float into fixed-point decimal of p fractions, rounded by mode.
+// Inf is error.
+func BigFloatToFixedPointDecimal(f *big.Float, p uint8, mode RoundingMode) (int64, error) {
+	if f.IsInf() {
+		return 0, errNotFinite
+	}
+	r, _ := f.Rat(nil)
+	return BigRatToFixedPointDecimal(r, p, mode)
+}
+
+// BigFloatToFixedPointDecimalUint is BigFloatToFixedPointDecimal for uint64, negative value is error.
+func BigFloatToFixedPointDecimalUint(f *big.Float, p uint8, mode RoundingMode) (uint64, error) {
+	if f.IsInf() {
+		return 0, errNotFinite
+	}
+	r, _ := f.Rat(nil)
+	return BigRatToFixedPointDecimalUint(r, p, mode)
+}
+
+// BigFloatToFixedPointDecimalWide is BigFloatToFixedPointDecimal for two's complement of high and low bits.
+func BigFloatToFixedPointDecimalWide(f *big.Float, p uint8, mode RoundingMode) (hi int64, lo uint64, err error) {
+	if f.IsInf() {
+		return 0, 0, errNotFinite
+	}
+	r, _ := f.Rat(nil)
+	return BigRatToFixedPointDecimalWide(r, p, mode)
+}
@@ -0,0 +1,160 @@
+package fpdecimal_test
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/nikolaydubina/fpdecimal"
+)
+
+func TestBigRatToFixedPointDecimal(t *testing.T) {
+	tests := []struct {
+		r    *big.Rat
+		p    uint8
+		mode fpdecimal.RoundingMode
+		v    int64
+	}{
+		{big.NewRat(0, 1), 3, fpdecimal.ToNearestEven, 0},
+		{big.NewRat(1, 3), 3, fpdecimal.ToNearestEven, 333},
+		{big.NewRat(2, 3), 3, fpdecimal.ToNearestEven, 667},
+		{big.NewRat(-2, 3), 3, fpdecimal.ToZero, -666},
+		{big.NewRat(1, 2000), 3, fpdecimal.ToNearestEven, 0},
+		{big.NewRat(3, 2000), 3, fpdecimal.ToNearestEven, 2},
+		{big.NewRat(-1, 2000), 3, fpdecimal.ToNearestAway, -1},
+		{big.NewRat(1, 1_000_000_000_000_000_000), 0, fpdecimal.ToPositiveInf, 1},
+		{big.NewRat(math.MaxInt64, 1000), 3, fpdecimal.ToNearestEven, math.MaxInt64},
+		{big.NewRat(math.MinInt64, 1000), 3, fpdecimal.ToNearestEven, math.MinInt64},
+	}
+	for _, tc := range tests {
+		t.Run(tc.r.String(), func(t *testing.T) {
+			v, err := fpdecimal.BigRatToFixedPointDecimal(tc.r, tc.p, tc.mode)
+			if err != nil || v != tc.v {
+				t.Error(v, tc.v, err)
+			}
+		})
+	}
+}
+
+func TestBigRatToFixedPointDecimal_Error(t *testing.T) {
+	tests := []*big.Rat{
+		big.NewRat(math.MaxInt64, 100),
+		new(big.Rat).SetFrac(new(big.Int).Lsh(big.NewInt(1), 63), big.NewInt(1000)),
+		new(big.Rat).Sub(big.NewRat(math.MinInt64, 1000), big.NewRat(1, 10_000)),
+	}
+	for _, tc := range tests {
+		t.Run(tc.String(), func(t *testing.T) {
+			v, err := fpdecimal.BigRatToFixedPointDecimal(tc, 3, fpdecimal.ToNegativeInf)
+			if err == nil || err.Error() != "overflow" || v != 0 {
+				t.Error(v, err)
+			}
+		})
+	}
+}
+
+func TestBigFloatToFixedPointDecimal(t *testing.T) {
+	if v, err := fpdecimal.BigFloatToFixedPointDecimal(big.NewFloat(0.0005), 3, fpdecimal.ToNearestEven); err != nil || v != 1 {
+		t.Error(v, err)
+	}
+
+	if v, err := fpdecimal.BigFloatToFixedPointDecimal(new(big.Float).SetInf(true), 3, fpdecimal.ToNearestEven); err == nil || v != 0 {
+		t.Error(v, err)
+	}
+}
+
+func FuzzBigRatToFixedPointDecimal(f *testing.F) {
+	tests := [][2]int64{
+		{1, 2},
+		{-15, 10},
+		{25, -10},
+		{math.MaxInt64, 3},
+		{math.MinInt64, 7},
+	}
+	for _, tc := range tests {
+		for _, mode := range roundingModes {
+			f.Add(tc[0], tc[1], uint8(mode))
+		}
+	}
+	f.Fuzz(func(t *testing.T, a, b int64, mode uint8) {
+		if b == 0 || (a == math.MinInt64 && b == -1) || mode > uint8(fpdecimal.ToPositiveInf) {
+			t.Skip()
+		}
+
+		v, err := fpdecimal.BigRatToFixedPointDecimal(big.NewRat(a, b), 0, fpdecimal.RoundingMode(mode))
+		if w := fpdecimal.DivRound(a, b, fpdecimal.RoundingMode(mode)); err != nil || v != w {
+			t.Error(a, b, mode, v, w, err)
+		}
+	})
+}
+
+func TestBigRatToFixedPointDecimalUint(t *testing.T) {
+	tests := []struct {
+		r    *big.Rat
+		mode fpdecimal.RoundingMode
+		v    uint64
+	}{
+		{big.NewRat(0, 1), fpdecimal.ToNearestEven, 0},
+		{big.NewRat(2, 3), fpdecimal.ToNearestEven, 667},
+		{big.NewRat(-1, 3000), fpdecimal.ToNearestEven, 0},
+		{new(big.Rat).SetFrac(new(big.Int).SetUint64(math.MaxUint64), big.NewInt(1000)), fpdecimal.ToZero, math.MaxUint64},
+	}
+	for _, tc := range tests {
+		t.Run(tc.r.String(), func(t *testing.T) {
+			if v, err := fpdecimal.BigRatToFixedPointDecimalUint(tc.r, 3, tc.mode); err != nil || v != tc.v {
+				t.Error(v, tc.v, err)
+			}
+		})
+	}
+
+	for _, r := range []*big.Rat{big.NewRat(-1, 1000), big.NewRat(-1, 3000), new(big.Rat).SetFrac(new(big.Int).SetUint64(math.MaxUint64), big.NewInt(999))} {
+		t.Run(r.String(), func(t *testing.T) {
+			if v, err := fpdecimal.BigRatToFixedPointDecimalUint(r, 3, fpdecimal.AwayFromZero); err == nil || v != 0 {
+				t.Error(v, err)
+			}
+		})
+	}
+
+	if v, err := fpdecimal.BigFloatToFixedPointDecimalUint(big.NewFloat(2.5), 3, fpdecimal.ToZero); err != nil || v != 2500 {
+		t.Error(v, err)
+	}
+	if v, err := fpdecimal.BigFloatToFixedPointDecimalUint(new(big.Float).SetInf(false), 3, fpdecimal.ToZero); err == nil || v != 0 {
+		t.Error(v, err)
+	}
+}
+
+func TestBigRatToFixedPointDecimalWide(t *testing.T) {
+	tests := []struct {
+		r    *big.Rat
+		mode fpdecimal.RoundingMode
+		v    string
+	}{
+		{big.NewRat(0, 1), fpdecimal.ToNearestEven, "0"},
+		{big.NewRat(2, 3), fpdecimal.ToNearestEven, "0.666666666666666667"},
+		{big.NewRat(-2, 3), fpdecimal.ToZero, "-0.666666666666666666"},
+		{new(big.Rat).SetFrac(wideBig(math.MaxInt64, math.MaxUint64), big.NewInt(1_000_000_000_000_000_000)), fpdecimal.ToZero, "170141183460469231731.687303715884105727"},
+		{new(big.Rat).SetFrac(wideBig(math.MinInt64, 0), big.NewInt(1_000_000_000_000_000_000)), fpdecimal.ToZero, "-170141183460469231731.687303715884105728"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.r.String(), func(t *testing.T) {
+			hi, lo, err := fpdecimal.BigRatToFixedPointDecimalWide(tc.r, 18, tc.mode)
+			if v := fpdecimal.FixedPointDecimalWideToString(hi, lo, 18); err != nil || v != tc.v {
+				t.Error(v, tc.v, err)
+			}
+		})
+	}
+
+	for _, r := range []*big.Rat{new(big.Rat).SetInt(wideBig(math.MaxInt64, math.MaxUint64)), new(big.Rat).SetFrac(wideBig(math.MinInt64, 0), big.NewInt(999_999_999_999_999_999))} {
+		t.Run(r.String(), func(t *testing.T) {
+			if hi, lo, err := fpdecimal.BigRatToFixedPointDecimalWide(r, 18, fpdecimal.ToZero); err == nil || err.Error() != "overflow" || hi != 0 || lo != 0 {
+				t.Error(hi, lo, err)
+			}
+		})
+	}
+
+	if hi, lo, err := fpdecimal.BigFloatToFixedPointDecimalWide(big.NewFloat(-2.5), 18, fpdecimal.ToZero); err != nil || fpdecimal.FixedPointDecimalWideToString(hi, lo, 18) != "-2.5" {
+		t.Error(hi, lo, err)
+	}
+	if hi, lo, err := fpdecimal.BigFloatToFixedPointDecimalWide(new(big.Float).SetInf(true), 18, fpdecimal.ToZero); err == nil || hi != 0 || lo != 0 {
+		t.Error(hi, lo, err)
+	}
+}
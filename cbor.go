@@ -0,0 +1,275 @@
+package fpdecimal
+
+import (
+	"math"
+	"math/bits"
+)
+
+// CBOR major types and tags, RFC 8949.
+const (
+	cborUint            = 0 << 5
+	cborNegInt          = 1 << 5
+	cborBytes           = 2 << 5
+	cborArray           = 4 << 5
+	cborTag             = 6 << 5
+	cborPosBignum       = 2
+	cborNegBignum       = 3
+	cborDecimalFraction = 4
+)
+
+var (
+	errCBORType         = &errorString{"cbor: not a decimal fraction or integer"}
+	errCBORShort        = &errorString{"cbor: unexpected end of data"}
+	errCBORTrailingData = &errorString{"cbor: trailing data"}
+)
+
+// AppendCBORDecimalFraction appends fixed-point decimal of p fractions as CBOR decimal fraction.
+// This is tag 4 with array of exponent and mantissa, as in RFC 8949 Section 3.4.4.
+// Exponent is always -p, so that mantissa is the same as v.
+func AppendCBORDecimalFraction(b []byte, v int64, p uint8) []byte {
+	b = append(b, cborTag|cborDecimalFraction, cborArray|2)
+	b = appendCBORInt(b, -int64(p))
+	return appendCBORInt(b, v)
+}
+
+// AppendCBORDecimalFractionUint is AppendCBORDecimalFraction for uint64.
+func AppendCBORDecimalFractionUint(b []byte, v uint64, p uint8) []byte {
+	b = append(b, cborTag|cborDecimalFraction, cborArray|2)
+	b = appendCBORInt(b, -int64(p))
+	return appendCBORHead(b, cborUint, v)
+}
+
+// ParseCBORDecimalFraction parses single CBOR decimal fraction into fixed-point decimal of p fractions.
+// Integers are accepted too.
+// Returns error when value does not fit or has more than p fractions.
+func ParseCBORDecimalFraction(b []byte, p uint8) (int64, error) {
+	hi, u, neg, err := parseCBORDecimalFraction(b, p, false)
+	if err != nil {
+		return 0, err
+	}
+	if hi != 0 {
+		return 0, errOverflow
+	}
+	return signed(u, neg)
+}
+
+// ParseCBORDecimalFractionUint is ParseCBORDecimalFraction for uint64, negative value is error.
+func ParseCBORDecimalFractionUint(b []byte, p uint8) (uint64, error) {
+	hi, u, neg, err := parseCBORDecimalFraction(b, p, false)
+	if err != nil {
+		return 0, err
+	}
+	if hi != 0 {
+		return 0, errOverflow
+	}
+	return unsigned(u, neg)
+}
+
+// AppendCBORDecimalFractionWide is AppendCBORDecimalFraction for 128 bits.
+// Mantissa that does not fit 64 bits is bignum, tag 2 or 3.
+func AppendCBORDecimalFractionWide(b []byte, hi int64, lo uint64, p uint8) []byte {
+	b = append(b, cborTag|cborDecimalFraction, cborArray|2)
+	b = appendCBORInt(b, -int64(p))
+
+	// negative is -1-n, which is bitwise not of two's complement
+	major, tag, uhi, ulo := byte(cborUint), byte(cborPosBignum), uint64(hi), lo
+	if hi < 0 {
+		major, tag, uhi, ulo = cborNegInt, cborNegBignum, ^uhi, ^ulo
+	}
+	if uhi == 0 {
+		return appendCBORHead(b, major, ulo)
+	}
+
+	n := 16 - bits.LeadingZeros64(uhi)/8
+	b = append(b, cborTag|tag)
+	b = appendCBORHead(b, cborBytes, uint64(n))
+	for i := n - 9; i >= 0; i-- {
+		b = append(b, byte(uhi>>(8*i)))
+	}
+	return append(b, byte(ulo>>56), byte(ulo>>48), byte(ulo>>40), byte(ulo>>32), byte(ulo>>24), byte(ulo>>16), byte(ulo>>8), byte(ulo))
+}
+
+// ParseCBORDecimalFractionWide is ParseCBORDecimalFraction for 128 bits.
+// Mantissa can be bignum, tag 2 or 3.
+func ParseCBORDecimalFractionWide(b []byte, p uint8) (hi int64, lo uint64, err error) {
+	uhi, ulo, neg, err := parseCBORDecimalFraction(b, p, true)
+	if err != nil {
+		return 0, 0, err
+	}
+	return signedWide(uhi, ulo, neg)
+}
+
+// parseCBORDecimalFraction returns 128-bit magnitude and sign of value.
+// Mantissa is bignum only when bignum is true, otherwise magnitude fits 64 bits.
+func parseCBORDecimalFraction(b []byte, p uint8, bignum bool) (hi, lo uint64, neg bool, err error) {
+	if len(b) == 0 {
+		return 0, 0, false, errCBORShort
+	}
+
+	if b[0] != cborTag|cborDecimalFraction {
+		mhi, mlo, neg, n, err := parseCBORMantissa(b, bignum)
+		if err != nil {
+			return 0, 0, false, err
+		}
+		if n != len(b) {
+			return 0, 0, false, errCBORTrailingData
+		}
+		hi, lo, err = scaleExpWide(mhi, mlo, 0, p)
+		return hi, lo, neg, err
+	}
+
+	if len(b) < 2 {
+		return 0, 0, false, errCBORShort
+	}
+	if b[1] != cborArray|2 {
+		return 0, 0, false, errCBORType
+	}
+	b = b[2:]
+
+	ue, eneg, n, err := parseCBORInt(b)
+	if err != nil {
+		return 0, 0, false, err
+	}
+	b = b[n:]
+
+	mhi, mlo, neg, n, err := parseCBORMantissa(b, bignum)
+	if err != nil {
+		return 0, 0, false, err
+	}
+	if n != len(b) {
+		return 0, 0, false, errCBORTrailingData
+	}
+
+	// exponents this large do not fit anyway, clamping keeps arithmetics safe
+	e := int(min(ue, math.MaxInt32))
+	if eneg {
+		e = -e
+	}
+
+	hi, lo, err = scaleExpWide(mhi, mlo, e, p)
+	return hi, lo, neg, err
+}
+
+func appendCBORInt(b []byte, v int64) []byte {
+	if v < 0 {
+		return appendCBORHead(b, cborNegInt, uint64(^v))
+	}
+	return appendCBORHead(b, cborUint, uint64(v))
+}
+
+func appendCBORHead(b []byte, major byte, v uint64) []byte {
+	switch {
+	case v < 24:
+		return append(b, major|byte(v))
+	case v <= math.MaxUint8:
+		return append(b, major|24, byte(v))
+	case v <= math.MaxUint16:
+		return append(b, major|25, byte(v>>8), byte(v))
+	case v <= math.MaxUint32:
+		return append(b, major|26, byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+	default:
+		return append(b, major|27, byte(v>>56), byte(v>>48), byte(v>>40), byte(v>>32), byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+	}
+}
+
+// parseCBORInt parses CBOR integer and returns its magnitude, sign and number of bytes read.
+func parseCBORInt(b []byte) (u uint64, neg bool, n int, err error) {
+	if len(b) == 0 {
+		return 0, false, 0, errCBORShort
+	}
+
+	major := b[0] & 0xe0
+	if major != cborUint && major != cborNegInt {
+		return 0, false, 0, errCBORType
+	}
+
+	u, n, err = parseCBORHead(b)
+	if err != nil {
+		return 0, false, 0, err
+	}
+
+	// negative integer is -1-u
+	if major == cborNegInt {
+		if u == math.MaxUint64 {
+			return 0, false, 0, errOverflow
+		}
+		return u + 1, true, n, nil
+	}
+	return u, false, n, nil
+}
+
+// parseCBORMantissa parses CBOR integer, or bignum of at most 128 bits when bignum is true.
+// Returns magnitude, sign and number of bytes read.
+func parseCBORMantissa(b []byte, bignum bool) (hi, lo uint64, neg bool, n int, err error) {
+	if !bignum || len(b) == 0 || (b[0] != cborTag|cborPosBignum && b[0] != cborTag|cborNegBignum) {
+		if bignum && len(b) > 0 && b[0]&0xe0 == cborNegInt {
+			// -1-u of largest u is -2^64, which fits 128 bits
+			u, n, err := parseCBORHead(b)
+			if err != nil {
+				return 0, 0, false, 0, err
+			}
+			lo, hi = bits.Add64(u, 1, 0)
+			return hi, lo, true, n, nil
+		}
+		lo, neg, n, err = parseCBORInt(b)
+		return 0, lo, neg, n, err
+	}
+	neg = b[0] == cborTag|cborNegBignum
+
+	if len(b) < 2 {
+		return 0, 0, false, 0, errCBORShort
+	}
+	if b[1]&0xe0 != cborBytes {
+		return 0, 0, false, 0, errCBORType
+	}
+	size, h, err := parseCBORHead(b[1:])
+	if err != nil {
+		return 0, 0, false, 0, err
+	}
+	n = 1 + h
+	if size > uint64(len(b)-n) {
+		return 0, 0, false, 0, errCBORShort
+	}
+	data := b[n : n+int(size)]
+	n += int(size)
+
+	for len(data) > 0 && data[0] == 0 {
+		data = data[1:]
+	}
+	if len(data) > 16 {
+		return 0, 0, false, 0, errOverflow
+	}
+	for _, q := range data {
+		hi, lo = hi<<8|lo>>56, lo<<8|uint64(q)
+	}
+
+	// negative bignum is -1-n
+	if neg {
+		var c uint64
+		lo, c = bits.Add64(lo, 1, 0)
+		hi, c = bits.Add64(hi, 0, c)
+		if c != 0 {
+			return 0, 0, false, 0, errOverflow
+		}
+	}
+	return hi, lo, neg, n, nil
+}
+
+// parseCBORHead parses argument of CBOR data item, and returns it with number of bytes read.
+func parseCBORHead(b []byte) (v uint64, n int, err error) {
+	switch info := b[0] & 0x1f; {
+	case info < 24:
+		return uint64(info), 1, nil
+	case info <= 27:
+		n = 1 + 1<<(info-24)
+		if len(b) < n {
+			return 0, 0, errCBORShort
+		}
+		for _, q := range b[1:n] {
+			v = v<<8 | uint64(q)
+		}
+		return v, n, nil
+	default:
+		return 0, 0, errCBORType
+	}
+}
@@ -0,0 +1,293 @@
+package fpdecimal_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math"
+	"testing"
+
+	"github.com/nikolaydubina/fpdecimal"
+)
+
+func TestAppendCBORDecimalFraction(t *testing.T) {
+	tests := []struct {
+		v int64
+		p uint8
+		b string
+	}{
+		{0, 3, "c4822200"},
+		{1, 3, "c4822201"},
+		{-1, 3, "c4822220"},
+		{273150, 3, "c482221a00042afe"},
+		{-273150, 3, "c482223a00042afd"},
+		{1500, 0, "c482001905dc"},
+		{9223372036854775807, 6, "c482251b7fffffffffffffff"},
+		{-9223372036854775808, 6, "c482253b7fffffffffffffff"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.b, func(t *testing.T) {
+			b := fpdecimal.AppendCBORDecimalFraction(nil, tc.v, tc.p)
+			if s := hex.EncodeToString(b); s != tc.b {
+				t.Error(s, tc.b)
+			}
+
+			v, err := fpdecimal.ParseCBORDecimalFraction(b, tc.p)
+			if err != nil {
+				t.Error(err)
+			}
+			if v != tc.v {
+				t.Error(v, tc.v)
+			}
+		})
+	}
+}
+
+func TestParseCBORDecimalFraction(t *testing.T) {
+	tests := []struct {
+		b string
+		p uint8
+		v int64
+	}{
+		// RFC 8949 Section 3.4.4 example 273.15
+		{"c48221196ab3", 3, 273150},
+		{"c48221196ab3", 2, 27315},
+		{"c48201196ab3", 3, 273150000},
+		{"c482241a00042acc", 3, 2731},
+		{"19012c", 3, 300000},
+		{"20", 3, -1000},
+		{"c4823b7fffffffffffffff00", 3, 0},
+	}
+	for _, tc := range tests {
+		t.Run(tc.b, func(t *testing.T) {
+			b, _ := hex.DecodeString(tc.b)
+			v, err := fpdecimal.ParseCBORDecimalFraction(b, tc.p)
+			if err != nil {
+				t.Error(err)
+			}
+			if v != tc.v {
+				t.Error(v, tc.v)
+			}
+		})
+	}
+}
+
+func TestParseCBORDecimalFraction_Error(t *testing.T) {
+	tests := []struct {
+		b   string
+		err string
+	}{
+		{"", "cbor: unexpected end of data"},
+		{"c4", "cbor: unexpected end of data"},
+		{"c482", "cbor: unexpected end of data"},
+		{"c48222", "cbor: unexpected end of data"},
+		{"c482221a0004", "cbor: unexpected end of data"},
+		{"c4832201", "cbor: not a decimal fraction or integer"},
+		{"f93c00", "cbor: not a decimal fraction or integer"},
+		{"c48222c249010000000000000000", "cbor: not a decimal fraction or integer"},
+		{"c482220101", "cbor: trailing data"},
+		{"0101", "cbor: trailing data"},
+		{"c48224196ab3", "more fraction digits than supported"},
+		{"c482131b7fffffffffffffff", "overflow"},
+		{"c482221bffffffffffffffff", "overflow"},
+		{"c482001b7fffffffffffffff", "overflow"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.b, func(t *testing.T) {
+			b, _ := hex.DecodeString(tc.b)
+			v, err := fpdecimal.ParseCBORDecimalFraction(b, 3)
+			if err == nil || err.Error() != tc.err {
+				t.Error(err)
+			}
+			if v != 0 {
+				t.Error(v)
+			}
+		})
+	}
+}
+
+func TestCBORDecimalFractionUint(t *testing.T) {
+	tests := []struct {
+		v uint64
+		b string
+	}{
+		{0, "c4822200"},
+		{273150, "c482221a00042afe"},
+		{18446744073709551615, "c482221bffffffffffffffff"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.b, func(t *testing.T) {
+			b := fpdecimal.AppendCBORDecimalFractionUint(nil, tc.v, 3)
+			if s := hex.EncodeToString(b); s != tc.b {
+				t.Error(s, tc.b)
+			}
+			if v, err := fpdecimal.ParseCBORDecimalFractionUint(b, 3); err != nil || v != tc.v {
+				t.Error(v, tc.v, err)
+			}
+		})
+	}
+
+	errs := []struct {
+		b   string
+		err string
+	}{
+		{"20", "negative value"},
+		{"c482223a00042afd", "negative value"},
+		{"3bffffffffffffffff", "overflow"},
+		{"c482001bffffffffffffffff", "overflow"},
+		{"c48222", "cbor: unexpected end of data"},
+	}
+	for _, tc := range errs {
+		t.Run(tc.b, func(t *testing.T) {
+			b, _ := hex.DecodeString(tc.b)
+			if v, err := fpdecimal.ParseCBORDecimalFractionUint(b, 3); err == nil || err.Error() != tc.err || v != 0 {
+				t.Error(v, err)
+			}
+		})
+	}
+}
+
+func FuzzParseCBORDecimalFraction(f *testing.F) {
+	tests := []string{
+		"c48221196ab3",
+		"c482221a00042afe",
+		"19012c",
+		"c482131b7fffffffffffffff",
+	}
+	for _, tc := range tests {
+		b, _ := hex.DecodeString(tc)
+		f.Add(b)
+	}
+	f.Fuzz(func(t *testing.T, b []byte) {
+		v, err := fpdecimal.ParseCBORDecimalFraction(b, 3)
+		if err != nil {
+			if v != 0 {
+				t.Error("has to be 0 on error")
+			}
+			return
+		}
+
+		q, err := fpdecimal.ParseCBORDecimalFraction(fpdecimal.AppendCBORDecimalFraction(nil, v, 3), 3)
+		if err != nil || q != v {
+			t.Error(v, q, err)
+		}
+	})
+}
+
+func TestCBORDecimalFractionWide(t *testing.T) {
+	tests := []struct {
+		hi int64
+		lo uint64
+		p  uint8
+		b  string
+	}{
+		{0, 0, 18, "c4823100"},
+		{0, 1500, 3, "c482221905dc"},
+		{-1, math.MaxUint64 - 1499, 3, "c482223905db"},
+		{0, math.MaxUint64, 0, "c482001bffffffffffffffff"},
+		{-1, 0, 0, "c482003bffffffffffffffff"},
+		{1, 0, 0, "c48200c249010000000000000000"},
+		{-2, math.MaxUint64, 0, "c48200c349010000000000000000"},
+		{math.MaxInt64, math.MaxUint64, 18, "c48231c2507fffffffffffffffffffffffffffffff"},
+		{math.MinInt64, 0, 18, "c48231c3507fffffffffffffffffffffffffffffff"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.b, func(t *testing.T) {
+			b := fpdecimal.AppendCBORDecimalFractionWide(nil, tc.hi, tc.lo, tc.p)
+			if s := hex.EncodeToString(b); s != tc.b {
+				t.Error(s, tc.b)
+			}
+			if hi, lo, err := fpdecimal.ParseCBORDecimalFractionWide(b, tc.p); err != nil || hi != tc.hi || lo != tc.lo {
+				t.Error(hi, lo, err)
+			}
+		})
+	}
+
+	parse := []struct {
+		b  string
+		hi int64
+		lo uint64
+	}{
+		{"c48200c240", 0, 0},
+		{"c48200c3410f", -1, math.MaxUint64 - 15},
+		{"c48200c2510000000000000000000000000000000001", 0, 1},
+		{"c48201c249010000000000000000", 10, 0},
+	}
+	for _, tc := range parse {
+		t.Run(tc.b, func(t *testing.T) {
+			b, _ := hex.DecodeString(tc.b)
+			if hi, lo, err := fpdecimal.ParseCBORDecimalFractionWide(b, 0); err != nil || hi != tc.hi || lo != tc.lo {
+				t.Error(hi, lo, err)
+			}
+		})
+	}
+
+	errs := []struct {
+		b   string
+		err string
+	}{
+		{"c48200c2510100000000000000000000000000000000", "overflow"},
+		{"c48200c250ffffffffffffffffffffffffffffffff", "overflow"},
+		{"c48200c35080000000000000000000000000000000", "overflow"},
+		{"c48200c2490100", "cbor: unexpected end of data"},
+		{"c48200c4820001", "cbor: not a decimal fraction or integer"},
+		{"c48226c24101", "more fraction digits than supported"},
+	}
+	for _, tc := range errs {
+		t.Run(tc.b, func(t *testing.T) {
+			b, _ := hex.DecodeString(tc.b)
+			if hi, lo, err := fpdecimal.ParseCBORDecimalFractionWide(b, 3); err == nil || err.Error() != tc.err || hi != 0 || lo != 0 {
+				t.Error(hi, lo, err)
+			}
+		})
+	}
+}
+
+func FuzzParseCBORDecimalFractionWide(f *testing.F) {
+	tests := []string{
+		"c48221196ab3",
+		"c48200c249010000000000000000",
+		"c48231c3507fffffffffffffffffffffffffffffff",
+		"19012c",
+	}
+	for _, tc := range tests {
+		b, _ := hex.DecodeString(tc)
+		f.Add(b)
+	}
+	f.Fuzz(func(t *testing.T, b []byte) {
+		hi, lo, err := fpdecimal.ParseCBORDecimalFractionWide(b, 3)
+		if err != nil {
+			if hi != 0 || lo != 0 {
+				t.Error("has to be 0 on error")
+			}
+			return
+		}
+
+		if v, err := fpdecimal.ParseCBORDecimalFraction(b, 3); err == nil && (hi != v>>63 || lo != uint64(v)) {
+			t.Error(v, hi, lo)
+		}
+
+		qhi, qlo, err := fpdecimal.ParseCBORDecimalFractionWide(fpdecimal.AppendCBORDecimalFractionWide(nil, hi, lo, 3), 3)
+		if err != nil || qhi != hi || qlo != lo {
+			t.Error(hi, lo, qhi, qlo, err)
+		}
+	})
+}
+
+func BenchmarkAppendCBORDecimalFraction(b *testing.B) {
+	d := make([]byte, 0, 32)
+	for n := 0; n < b.N; n++ {
+		d = fpdecimal.AppendCBORDecimalFraction(d[:0], 123456789, 3)
+	}
+	if !bytes.HasPrefix(d, []byte{0xc4}) {
+		b.Error(d)
+	}
+}
+
+func BenchmarkParseCBORDecimalFraction(b *testing.B) {
+	d := fpdecimal.AppendCBORDecimalFraction(nil, 123456789, 3)
+	for n := 0; n < b.N; n++ {
+		if v, err := fpdecimal.ParseCBORDecimalFraction(d, 3); err != nil || v != 123456789 {
+			b.Error(v, err)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+// Command fpgen generates package with fixed-point decimal type of given number of fractional digits.
+// Package has same API as fp3, including arithmetics, encodings, tests, fuzz tests and benchmarks.
+// Unsigned package has same API as ufp3.
+//
+// Usage:
+//
+//	//go:generate go run github.com/nikolaydubina/fpdecimal/cmd/fpgen -type BasisPoints -pkg bps -digits 4 -o bps
+//
+// Flags:
+//
+//	-type      name of type (default Decimal)
+//	-pkg       name of package (default name of output directory)
+//	-digits    number of fractional digits, from 0 to 18
+//	-o         output directory (default current directory)
+//	-import    import path of package, used in tests (default from go.mod)
+//	-unsigned  uint64 that can never be negative, as ufp3
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/nikolaydubina/fpdecimal/internal/gen"
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("fpgen: ")
+	if err := run(os.Args[1:]); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(args []string) error {
+	var (
+		c      gen.Config
+		digits int
+		out    string
+	)
+	fs := flag.NewFlagSet("fpgen", flag.ContinueOnError)
+	fs.StringVar(&c.Type, "type", "Decimal", "name of type")
+	fs.StringVar(&c.Package, "pkg", "", "name of package (default name of output directory)")
+	fs.IntVar(&digits, "digits", 0, "number of fractional digits, from 0 to 18")
+	fs.StringVar(&out, "o", ".", "output directory")
+	fs.StringVar(&c.Import, "import", "", "import path of package, used in tests (default from go.mod)")
+	fs.BoolVar(&c.Unsigned, "unsigned", false, "uint64 that can never be negative, as ufp3")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	hasDigits := false
+	fs.Visit(func(f *flag.Flag) { hasDigits = hasDigits || f.Name == "digits" })
+	if !hasDigits || digits < 0 || digits > gen.MaxDigits {
+		return fmt.Errorf("digits has to be from 0 to %d", gen.MaxDigits)
+	}
+	c.Digits = uint8(digits)
+
+	dir, err := filepath.Abs(out)
+	if err != nil {
+		return err
+	}
+	if c.Package == "" {
+		c.Package = filepath.Base(dir)
+	}
+	if c.Import == "" {
+		if c.Import, err = importPath(dir); err != nil {
+			return err
+		}
+	}
+
+	files, err := gen.Files(c)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	for name, src := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), src, 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// importPath of directory, from module path in nearest go.mod.
+func importPath(dir string) (string, error) {
+	for rel := ""; ; {
+		module, err := modulePath(filepath.Join(dir, "go.mod"))
+		if err == nil {
+			return path.Join(module, rel), nil
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return "", err
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", errors.New("go.mod not found, set -import")
+		}
+		rel = path.Join(filepath.Base(dir), rel)
+		dir = parent
+	}
+}
+
+func modulePath(name string) (string, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if module, ok := strings.CutPrefix(line, "module"); ok && module != "" && (module[0] == ' ' || module[0] == '\t') {
+			module, _, _ = strings.Cut(module, "//")
+			return strings.Trim(strings.TrimSpace(module), `"`), nil
+		}
+	}
+	if err := s.Err(); err != nil {
+		return "", err
+	}
+	return "", fmt.Errorf("%s: missing module", name)
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestImportPath(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("// comment\nmodule \"example.com/finance\" // comment\n\ngo 1.23\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		dir  string
+		path string
+	}{
+		{dir, "example.com/finance"},
+		{filepath.Join(dir, "bps"), "example.com/finance/bps"},
+		{filepath.Join(dir, "internal", "bps"), "example.com/finance/internal/bps"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.path, func(t *testing.T) {
+			if p, err := importPath(tc.dir); err != nil || p != tc.path {
+				t.Error(p, err)
+			}
+		})
+	}
+}
+
+func TestRun_Error(t *testing.T) {
+	tests := [][]string{
+		{"-pkg", "bps"},
+		{"-pkg", "bps", "-digits", "19"},
+		{"-pkg", "bps", "-digits", "4", "-type", "Basis Points"},
+		{"-pkg", "b-p-s", "-digits", "4"},
+		{"-unknown"},
+	}
+	for _, tc := range tests {
+		t.Run(strings.Join(tc, " "), func(t *testing.T) {
+			if err := run(append(tc, "-o", t.TempDir(), "-import", "example.com/bps")); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+// TestRun generates package in new module and runs its tests.
+func TestRun(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	root, err := filepath.Abs(filepath.Join("..", ".."))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	mod := "module example.com/finance\n\ngo 1.23\n\nrequire github.com/nikolaydubina/fpdecimal v0.0.0\n\nreplace github.com/nikolaydubina/fpdecimal => " + root + "\n"
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte(mod), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := run([]string{"-type", "BasisPoints", "-digits", "4", "-o", filepath.Join(dir, "bps")}); err != nil {
+		t.Fatal(err)
+	}
+
+	src, err := os.ReadFile(filepath.Join(dir, "bps", "fpdecimal.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{`// Code generated by "fpgen -type BasisPoints -pkg bps -digits 4"; DO NOT EDIT.`, "package bps", "type BasisPoints struct{ v int64 }"} {
+		if !strings.Contains(string(src), s) {
+			t.Error(s)
+		}
+	}
+
+	cmd := exec.Command("go", "test", "./...")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Error(err, string(out))
+	}
+}
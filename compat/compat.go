@@ -0,0 +1,113 @@
+// Package compat converts fixed-point decimals to and from github.com/shopspring/decimal and github.com/cockroachdb/apd.
+//
+// It is separate module, this keeps fpdecimal free of dependencies.
+// All conversions are lossless, values that can not be represented exactly result in error.
+// This allows to migrate code incrementally.
+package compat
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/cockroachdb/apd/v3"
+	"github.com/shopspring/decimal"
+
+	"github.com/nikolaydubina/fpdecimal/fp3"
+	"github.com/nikolaydubina/fpdecimal/fp6"
+)
+
+var (
+	ErrPrecision = errors.New("value has more fractional digits than supported")
+	ErrOverflow  = errors.New("value out of range")
+	ErrNotFinite = errors.New("value is not finite")
+)
+
+// FP3ToShopspring returns decimal of same value.
+func FP3ToShopspring(d fp3.Decimal) decimal.Decimal { return decimal.New(d.Scaled(), -3) }
+
+// FP3FromShopspring converts decimal.
+func FP3FromShopspring(d decimal.Decimal) (fp3.Decimal, error) {
+	v, err := fromCoefficient(d.Coefficient(), d.Exponent(), 3)
+	if err != nil {
+		return fp3.Zero, err
+	}
+	return fp3.FromIntScaled(v), nil
+}
+
+// FP3ToAPD returns decimal of same value.
+func FP3ToAPD(d fp3.Decimal) *apd.Decimal { return apd.New(d.Scaled(), -3) }
+
+// FP3FromAPD converts decimal, NaN and Infinity result in error.
+func FP3FromAPD(d *apd.Decimal) (fp3.Decimal, error) {
+	v, err := fromAPD(d, 3)
+	if err != nil {
+		return fp3.Zero, err
+	}
+	return fp3.FromIntScaled(v), nil
+}
+
+// FP6ToShopspring returns decimal of same value.
+func FP6ToShopspring(d fp6.Decimal) decimal.Decimal { return decimal.New(d.Scaled(), -6) }
+
+// FP6FromShopspring converts decimal.
+func FP6FromShopspring(d decimal.Decimal) (fp6.Decimal, error) {
+	v, err := fromCoefficient(d.Coefficient(), d.Exponent(), 6)
+	if err != nil {
+		return fp6.Zero, err
+	}
+	return fp6.FromIntScaled(v), nil
+}
+
+// FP6ToAPD returns decimal of same value.
+func FP6ToAPD(d fp6.Decimal) *apd.Decimal { return apd.New(d.Scaled(), -6) }
+
+// FP6FromAPD converts decimal, NaN and Infinity result in error.
+func FP6FromAPD(d *apd.Decimal) (fp6.Decimal, error) {
+	v, err := fromAPD(d, 6)
+	if err != nil {
+		return fp6.Zero, err
+	}
+	return fp6.FromIntScaled(v), nil
+}
+
+func fromAPD(d *apd.Decimal, p uint8) (int64, error) {
+	if d.Form != apd.Finite {
+		return 0, ErrNotFinite
+	}
+	c := d.Coeff.MathBigInt()
+	if d.Negative {
+		c.Neg(c)
+	}
+	return fromCoefficient(c, d.Exponent, p)
+}
+
+// fromCoefficient converts c×10^e into scaled value of p fractions.
+// Trailing zeros beyond p fractions are allowed, other digits result in error.
+func fromCoefficient(c *big.Int, e int32, p uint8) (int64, error) {
+	if c.Sign() == 0 {
+		return 0, nil
+	}
+
+	k := int64(e) + int64(p)
+	switch {
+	case k > 18:
+		// even smallest coefficient does not fit
+		return 0, ErrOverflow
+	case k >= 0:
+		c = new(big.Int).Mul(c, new(big.Int).Exp(big.NewInt(10), big.NewInt(k), nil))
+	case int64(c.BitLen()) <= -3*k:
+		// |c| < 2^(-3k) < 10^-k, so it is not divisible
+		return 0, ErrPrecision
+	default:
+		m := new(big.Int)
+		c, m = new(big.Int).QuoRem(c, new(big.Int).Exp(big.NewInt(10), big.NewInt(-k), nil), m)
+		if m.Sign() != 0 {
+			return 0, ErrPrecision
+		}
+	}
+
+	if !c.IsInt64() {
+		return 0, ErrOverflow
+	}
+	return c.Int64(), nil
+}
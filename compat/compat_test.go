@@ -0,0 +1,192 @@
+package compat_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/cockroachdb/apd/v3"
+	"github.com/shopspring/decimal"
+
+	"github.com/nikolaydubina/fpdecimal/compat"
+	"github.com/nikolaydubina/fpdecimal/fp3"
+	"github.com/nikolaydubina/fpdecimal/fp6"
+)
+
+func TestFP3Shopspring(t *testing.T) {
+	tests := []struct {
+		s string
+		v int64
+	}{
+		{"0", 0},
+		{"-0.000", 0},
+		{"1", 1000},
+		{"-12.5", -12500},
+		{"0.001", 1},
+		{"1.500000000", 1500},
+		{"1.5e3", 1_500_000},
+		{"9223372036854775.807", 9223372036854775807},
+		{"-9223372036854775.808", -9223372036854775808},
+	}
+	for _, tc := range tests {
+		t.Run(tc.s, func(t *testing.T) {
+			d := decimal.RequireFromString(tc.s)
+
+			v, err := compat.FP3FromShopspring(d)
+			if err != nil || v.Scaled() != tc.v {
+				t.Error(v, err)
+			}
+
+			if q := compat.FP3ToShopspring(v); !q.Equal(d) {
+				t.Error(q, d)
+			}
+		})
+	}
+}
+
+func TestFP3APD(t *testing.T) {
+	tests := []struct {
+		s string
+		v int64
+	}{
+		{"0", 0},
+		{"-0", 0},
+		{"-12.5", -12500},
+		{"0.001", 1},
+		{"1.500000000", 1500},
+		{"15E-1", 1500},
+		{"9223372036854775.807", 9223372036854775807},
+		{"-9223372036854775.808", -9223372036854775808},
+	}
+	for _, tc := range tests {
+		t.Run(tc.s, func(t *testing.T) {
+			d, _, err := apd.NewFromString(tc.s)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			v, err := compat.FP3FromAPD(d)
+			if err != nil || v.Scaled() != tc.v {
+				t.Error(v, err)
+			}
+
+			if q := compat.FP3ToAPD(v); q.Cmp(d) != 0 {
+				t.Error(q, d)
+			}
+		})
+	}
+}
+
+func TestFP3From_Error(t *testing.T) {
+	tests := []struct {
+		s   string
+		err error
+	}{
+		{"0.0001", compat.ErrPrecision},
+		{"1.0005", compat.ErrPrecision},
+		{"1e-4", compat.ErrPrecision},
+		{"1e-99999", compat.ErrPrecision},
+		{"9223372036854775.808", compat.ErrOverflow},
+		{"-9223372036854775.809", compat.ErrOverflow},
+		{"1e16", compat.ErrOverflow},
+		{"1e99999", compat.ErrOverflow},
+	}
+	for _, tc := range tests {
+		t.Run(tc.s, func(t *testing.T) {
+			v, err := compat.FP3FromShopspring(decimal.RequireFromString(tc.s))
+			if !errors.Is(err, tc.err) || v != fp3.Zero {
+				t.Error(v, err)
+			}
+
+			d, _, err := apd.NewFromString(tc.s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			v, err = compat.FP3FromAPD(d)
+			if !errors.Is(err, tc.err) || v != fp3.Zero {
+				t.Error(v, err)
+			}
+		})
+	}
+
+	for _, s := range []string{"NaN", "Infinity", "-Infinity", "sNaN"} {
+		d, _, _ := apd.NewFromString(s)
+		if v, err := compat.FP3FromAPD(d); !errors.Is(err, compat.ErrNotFinite) || v != fp3.Zero {
+			t.Error(s, v, err)
+		}
+	}
+}
+
+func TestFP6(t *testing.T) {
+	v, err := compat.FP6FromShopspring(decimal.RequireFromString("-1.000001"))
+	if err != nil || v.Scaled() != -1_000_001 {
+		t.Error(v, err)
+	}
+
+	if _, err := compat.FP6FromShopspring(decimal.RequireFromString("1.0000001")); !errors.Is(err, compat.ErrPrecision) {
+		t.Error(err)
+	}
+
+	d, _, _ := apd.NewFromString("0.000005")
+	if v, err := compat.FP6FromAPD(d); err != nil || v.Scaled() != 5 {
+		t.Error(v, err)
+	}
+
+	if _, err := compat.FP6FromAPD(apd.New(1, 13)); !errors.Is(err, compat.ErrOverflow) {
+		t.Error(err)
+	}
+}
+
+func FuzzFP3(f *testing.F) {
+	tests := []int64{0, 1, 999, 1000, 1001, 123456789, 9223372036854775807}
+	for _, tc := range tests {
+		f.Add(tc)
+		f.Add(-tc)
+	}
+	f.Fuzz(func(t *testing.T, a int64) {
+		d := fp3.FromIntScaled(a)
+
+		if v, err := compat.FP3FromShopspring(compat.FP3ToShopspring(d)); err != nil || v != d {
+			t.Error(d, v, err)
+		}
+		if s := compat.FP3ToShopspring(d).String(); s != d.String() {
+			t.Error(s, d)
+		}
+
+		if v, err := compat.FP3FromAPD(compat.FP3ToAPD(d)); err != nil || v != d {
+			t.Error(d, v, err)
+		}
+	})
+}
+
+func FuzzFP6(f *testing.F) {
+	tests := []int64{0, 1, 999, 1000, 1001, 123456789, 9223372036854775807}
+	for _, tc := range tests {
+		f.Add(tc)
+		f.Add(-tc)
+	}
+	f.Fuzz(func(t *testing.T, a int64) {
+		d := fp6.FromIntScaled(a)
+
+		if v, err := compat.FP6FromShopspring(compat.FP6ToShopspring(d)); err != nil || v != d {
+			t.Error(d, v, err)
+		}
+
+		if v, err := compat.FP6FromAPD(compat.FP6ToAPD(d)); err != nil || v != d {
+			t.Error(d, v, err)
+		}
+	})
+}
+
+func ExampleFP3FromShopspring() {
+	total := decimal.RequireFromString("10.25").Add(decimal.RequireFromString("0.125"))
+
+	v, err := compat.FP3FromShopspring(total)
+	fmt.Println(v, err)
+
+	_, err = compat.FP3FromShopspring(total.Div(decimal.NewFromInt(3)))
+	fmt.Println(err)
+	// Output:
+	// 10.375 <nil>
+	// value has more fractional digits than supported
+}
@@ -0,0 +1,12 @@
+module github.com/nikolaydubina/fpdecimal/compat
+
+go 1.23
+
+// fp3 and fp6 are not released yet, replace with released version that has them once tagged.
+replace github.com/nikolaydubina/fpdecimal => ../
+
+require (
+	github.com/cockroachdb/apd/v3 v3.2.1
+	github.com/nikolaydubina/fpdecimal v0.0.0-00010101000000-000000000000
+	github.com/shopspring/decimal v1.4.0
+)
@@ -0,0 +1,225 @@
+// Package csvdec reads and writes decimal columns of CSV.
+//
+// Fields are parsed and printed in place, without allocating strings per field.
+// This is useful for large CSV exports.
+package csvdec
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"unsafe"
+
+	"github.com/nikolaydubina/fpdecimal"
+	"github.com/nikolaydubina/fpdecimal/fp3"
+	"github.com/nikolaydubina/fpdecimal/fp6"
+)
+
+var (
+	ErrNoHeader      = errors.New("csvdec: header not read")
+	ErrUnknownColumn = errors.New("csvdec: unknown column")
+	ErrNoColumn      = errors.New("csvdec: column index out of range")
+)
+
+// ParseError is error of decoding field.
+type ParseError struct {
+	Line   int    // line of field, starting from 1
+	Column int    // index of field, starting from 0
+	Name   string // name of column, when header is read
+	Err    error
+}
+
+func (e *ParseError) Error() string {
+	if e.Name != "" {
+		return fmt.Sprintf("csvdec: line %d column %d (%s): %v", e.Line, e.Column, e.Name, e.Err)
+	}
+	return fmt.Sprintf("csvdec: line %d column %d: %v", e.Line, e.Column, e.Err)
+}
+
+func (e *ParseError) Unwrap() error { return e.Err }
+
+// Reader decodes decimal fields of CSV records.
+type Reader struct {
+	r      *csv.Reader
+	header []string
+	record []string
+}
+
+// NewReader wraps CSV reader.
+// Records are reused between reads, so strings of fields are valid only until next read.
+func NewReader(r *csv.Reader) *Reader {
+	r.ReuseRecord = true
+	return &Reader{r: r}
+}
+
+// ReadHeader reads record with names of columns.
+func (r *Reader) ReadHeader() error {
+	record, err := r.r.Read()
+	if err != nil {
+		return err
+	}
+	r.header = append(r.header[:0], record...)
+	return nil
+}
+
+// Header returns names of columns.
+func (r *Reader) Header() []string { return r.header }
+
+// Index returns index of named column.
+func (r *Reader) Index(name string) (int, error) {
+	if r.header == nil {
+		return 0, ErrNoHeader
+	}
+	for i, q := range r.header {
+		if q == name {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: %s", ErrUnknownColumn, name)
+}
+
+// Read reads next record.
+// Returns io.EOF when there are no more records.
+func (r *Reader) Read() (err error) {
+	r.record, err = r.r.Read()
+	return err
+}
+
+// Record returns current record.
+func (r *Reader) Record() []string { return r.record }
+
+// FP3 decodes field of current record.
+func (r *Reader) FP3(col int) (fp3.Decimal, error) {
+	v, err := r.parse(col, 3)
+	return fp3.FromIntScaled(v), err
+}
+
+// FP6 decodes field of current record.
+func (r *Reader) FP6(col int) (fp6.Decimal, error) {
+	v, err := r.parse(col, 6)
+	return fp6.FromIntScaled(v), err
+}
+
+func (r *Reader) parse(col int, p uint8) (int64, error) {
+	if col < 0 || col >= len(r.record) {
+		return 0, r.error(col, ErrNoColumn)
+	}
+	s := r.record[col]
+	v, err := fpdecimal.ParseFixedPointDecimal(unsafe.Slice(unsafe.StringData(s), len(s)), p)
+	if err != nil {
+		return 0, r.error(col, err)
+	}
+	return v, nil
+}
+
+func (r *Reader) error(col int, err error) error {
+	e := ParseError{Column: col, Err: err}
+	if col >= 0 && col < len(r.record) {
+		e.Line, _ = r.r.FieldPos(col)
+	} else if len(r.record) > 0 {
+		e.Line, _ = r.r.FieldPos(0)
+	}
+	if col >= 0 && col < len(r.header) {
+		e.Name = r.header[col]
+	}
+	return &e
+}
+
+// ReadAllFP3 reads remaining records and decodes columns.
+// Values of each column are appended to slice at same position as column.
+func (r *Reader) ReadAllFP3(cols ...int) ([][]fp3.Decimal, error) {
+	vs := make([][]fp3.Decimal, len(cols))
+	for {
+		if err := r.Read(); err != nil {
+			if err == io.EOF {
+				return vs, nil
+			}
+			return vs, err
+		}
+		for i, col := range cols {
+			v, err := r.FP3(col)
+			if err != nil {
+				return vs, err
+			}
+			vs[i] = append(vs[i], v)
+		}
+	}
+}
+
+// ReadAllFP6 reads remaining records and decodes columns.
+// Values of each column are appended to slice at same position as column.
+func (r *Reader) ReadAllFP6(cols ...int) ([][]fp6.Decimal, error) {
+	vs := make([][]fp6.Decimal, len(cols))
+	for {
+		if err := r.Read(); err != nil {
+			if err == io.EOF {
+				return vs, nil
+			}
+			return vs, err
+		}
+		for i, col := range cols {
+			v, err := r.FP6(col)
+			if err != nil {
+				return vs, err
+			}
+			vs[i] = append(vs[i], v)
+		}
+	}
+}
+
+// Writer encodes records with decimal fields.
+// Fields are appended to current record, and written together.
+type Writer struct {
+	w      *csv.Writer
+	buf    []byte
+	ends   []int
+	record []string
+}
+
+// NewWriter wraps CSV writer.
+func NewWriter(w *csv.Writer) *Writer { return &Writer{w: w} }
+
+// AppendFP3 appends field to current record.
+func (w *Writer) AppendFP3(v fp3.Decimal) {
+	w.buf = fpdecimal.AppendFixedPointDecimal(w.buf, v.Scaled(), 3)
+	w.ends = append(w.ends, len(w.buf))
+}
+
+// AppendFP6 appends field to current record.
+func (w *Writer) AppendFP6(v fp6.Decimal) {
+	w.buf = fpdecimal.AppendFixedPointDecimal(w.buf, v.Scaled(), 6)
+	w.ends = append(w.ends, len(w.buf))
+}
+
+// AppendString appends field to current record.
+func (w *Writer) AppendString(s string) {
+	w.buf = append(w.buf, s...)
+	w.ends = append(w.ends, len(w.buf))
+}
+
+// WriteRecord writes current record and starts new one.
+func (w *Writer) WriteRecord() error {
+	w.record = w.record[:0]
+	start := 0
+	for _, end := range w.ends {
+		var s string
+		if end > start {
+			s = unsafe.String(&w.buf[start], end-start)
+		}
+		w.record = append(w.record, s)
+		start = end
+	}
+
+	// fields are copied by writer, so buffer can be reused
+	err := w.w.Write(w.record)
+
+	w.buf, w.ends = w.buf[:0], w.ends[:0]
+	return err
+}
+
+// Flush writes buffered data and returns error of any previous write or flush.
+func (w *Writer) Flush() error {
+	w.w.Flush()
+	return w.w.Error()
+}
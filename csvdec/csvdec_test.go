@@ -0,0 +1,263 @@
+package csvdec_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/nikolaydubina/fpdecimal/csvdec"
+	"github.com/nikolaydubina/fpdecimal/fp3"
+	"github.com/nikolaydubina/fpdecimal/fp6"
+)
+
+const transactions = `id,amount,fee,rate
+1,100.50,0.25,1.000001
+2,-20,0,0.5
+3,"1000.125",0.001,2
+`
+
+func TestReader_ReadAllFP3(t *testing.T) {
+	r := csvdec.NewReader(csv.NewReader(strings.NewReader(transactions)))
+	if err := r.ReadHeader(); err != nil {
+		t.Fatal(err)
+	}
+
+	amount, err := r.Index("amount")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vs, err := r.ReadAllFP3(amount, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if s := fmt.Sprint(vs); s != "[[100.5 -20 1000.125] [0.25 0 0.001]]" {
+		t.Error(s)
+	}
+}
+
+func TestReader_ReadAllFP6(t *testing.T) {
+	r := csvdec.NewReader(csv.NewReader(strings.NewReader(transactions)))
+	if err := r.ReadHeader(); err != nil {
+		t.Fatal(err)
+	}
+
+	vs, err := r.ReadAllFP6(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if s := fmt.Sprint(vs); s != "[[1.000001 0.5 2]]" {
+		t.Error(s)
+	}
+}
+
+func TestReader_Error(t *testing.T) {
+	t.Run("when bad digit, then line and column", func(t *testing.T) {
+		r := csvdec.NewReader(csv.NewReader(strings.NewReader("id,amount\n1,10\n2,\"1\n0\"\n")))
+		if err := r.ReadHeader(); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err := r.ReadAllFP3(1)
+
+		var e *csvdec.ParseError
+		if !errors.As(err, &e) {
+			t.Fatal(err)
+		}
+		if e.Line != 3 || e.Column != 1 || e.Name != "amount" {
+			t.Error(e)
+		}
+		if s := err.Error(); s != "csvdec: line 3 column 1 (amount): bad digit" {
+			t.Error(s)
+		}
+	})
+
+	t.Run("when no header, then line and column without name", func(t *testing.T) {
+		r := csvdec.NewReader(csv.NewReader(strings.NewReader("1,10\n2,x\n")))
+
+		_, err := r.ReadAllFP3(1)
+		if s := err.Error(); s != "csvdec: line 2 column 1: bad digit" {
+			t.Error(s)
+		}
+	})
+
+	t.Run("when column out of range, then error", func(t *testing.T) {
+		r := csvdec.NewReader(csv.NewReader(strings.NewReader("1,10\n")))
+
+		_, err := r.ReadAllFP6(2)
+		if !errors.Is(err, csvdec.ErrNoColumn) {
+			t.Error(err)
+		}
+	})
+
+	t.Run("when unknown column, then error", func(t *testing.T) {
+		r := csvdec.NewReader(csv.NewReader(strings.NewReader("id,amount\n")))
+		if err := r.ReadHeader(); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := r.Index("fee"); !errors.Is(err, csvdec.ErrUnknownColumn) {
+			t.Error(err)
+		}
+	})
+
+	t.Run("when header not read, then error", func(t *testing.T) {
+		r := csvdec.NewReader(csv.NewReader(strings.NewReader("id,amount\n")))
+		if _, err := r.Index("amount"); !errors.Is(err, csvdec.ErrNoHeader) {
+			t.Error(err)
+		}
+	})
+
+	t.Run("when bad csv, then error", func(t *testing.T) {
+		r := csvdec.NewReader(csv.NewReader(strings.NewReader("1,10\n2\n")))
+		if _, err := r.ReadAllFP3(1); err == nil {
+			t.Error("expected error")
+		}
+	})
+}
+
+func TestWriter(t *testing.T) {
+	var b bytes.Buffer
+	w := csvdec.NewWriter(csv.NewWriter(&b))
+
+	w.AppendString("id")
+	w.AppendString("amount")
+	w.AppendString("rate")
+	if err := w.WriteRecord(); err != nil {
+		t.Error(err)
+	}
+
+	w.AppendString("1")
+	w.AppendFP3(fp3.FromFloat(-100.5))
+	w.AppendFP6(fp6.FromIntScaled(1_000_001))
+	if err := w.WriteRecord(); err != nil {
+		t.Error(err)
+	}
+
+	w.AppendString("")
+	w.AppendFP3(fp3.Zero)
+	w.AppendString("a,b")
+	if err := w.WriteRecord(); err != nil {
+		t.Error(err)
+	}
+
+	if err := w.Flush(); err != nil {
+		t.Error(err)
+	}
+
+	if s := b.String(); s != "id,amount,rate\n1,-100.5,1.000001\n,0,\"a,b\"\n" {
+		t.Error(s)
+	}
+}
+
+func FuzzReadWrite(f *testing.F) {
+	f.Add(int64(0), int64(1))
+	f.Add(int64(-1000), int64(123456789))
+	f.Fuzz(func(t *testing.T, a, b int64) {
+		var buf bytes.Buffer
+		w := csvdec.NewWriter(csv.NewWriter(&buf))
+		w.AppendFP3(fp3.FromIntScaled(a))
+		w.AppendFP6(fp6.FromIntScaled(b))
+		if err := w.WriteRecord(); err != nil {
+			t.Error(err)
+		}
+		if err := w.Flush(); err != nil {
+			t.Error(err)
+		}
+
+		r := csvdec.NewReader(csv.NewReader(&buf))
+		if err := r.Read(); err != nil {
+			t.Fatal(err)
+		}
+		if v, err := r.FP3(0); err != nil || v.Scaled() != a {
+			t.Error(a, v, err)
+		}
+		if v, err := r.FP6(1); err != nil || v.Scaled() != b {
+			t.Error(b, v, err)
+		}
+		if err := r.Read(); err != io.EOF {
+			t.Error(err)
+		}
+	})
+}
+
+func BenchmarkReader_FP3(b *testing.B) {
+	var data strings.Builder
+	for i := 0; i < 1000; i++ {
+		data.WriteString("123.456,-0.001,100\n")
+	}
+	s := data.String()
+
+	b.ResetTimer()
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		r := csvdec.NewReader(csv.NewReader(strings.NewReader(s)))
+		for r.Read() == nil {
+			for col := 0; col < 3; col++ {
+				if _, err := r.FP3(col); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	}
+}
+
+func BenchmarkWriter_FP3(b *testing.B) {
+	w := csvdec.NewWriter(csv.NewWriter(io.Discard))
+	v := fp3.FromIntScaled(123456)
+
+	b.ResetTimer()
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		w.AppendFP3(v)
+		w.AppendFP3(v)
+		w.AppendFP3(v)
+		if err := w.WriteRecord(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func ExampleReader() {
+	r := csvdec.NewReader(csv.NewReader(strings.NewReader(transactions)))
+	if err := r.ReadHeader(); err != nil {
+		log.Fatal(err)
+	}
+	amount, err := r.Index("amount")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	total := fp3.Zero
+	for r.Read() == nil {
+		v, err := r.FP3(amount)
+		if err != nil {
+			log.Fatal(err)
+		}
+		total = total.Add(v)
+	}
+
+	fmt.Println(total)
+	// Output: 1080.625
+}
+
+func ExampleWriter() {
+	w := csvdec.NewWriter(csv.NewWriter(os.Stdout))
+
+	w.AppendString("EUR")
+	w.AppendFP3(fp3.FromFloat(12.5))
+	if err := w.WriteRecord(); err != nil {
+		log.Fatal(err)
+	}
+	if err := w.Flush(); err != nil {
+		log.Fatal(err)
+	}
+	// Output: EUR,12.5
+}
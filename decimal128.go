@@ -0,0 +1,122 @@
+package fpdecimal
+
+// IEEE 754-2008 decimal128 in binary integer decimal (BID) encoding.
+const (
+	decimal128ExponentBias = 6176
+	decimal128ExponentMask = 1<<14 - 1
+	decimal128CoeffHiMask  = 1<<49 - 1
+	decimal128SignBit      = 1 << 63
+)
+
+// largest canonical coefficient 10^34-1
+const (
+	decimal128MaxCoeffHi = 0x1ed09bead87c0
+	decimal128MaxCoeffLo = 0x378d8e63ffffffff
+)
+
+var (
+	errDecimal128Inf    = &errorString{"decimal128: infinity"}
+	errDecimal128NaN    = &errorString{"decimal128: NaN"}
+	errDecimal128Digits = &errorString{"decimal128: more than 34 digits"}
+)
+
+// FixedPointDecimalToDecimal128 converts fixed-point decimal of p fractions to IEEE 754-2008 decimal128 in BID encoding.
+// Returns high and low 64 bits, as in BSON Decimal128.
+// Exponent is always -p, so that coefficient is the same as absolute value of v.
+func FixedPointDecimalToDecimal128(v int64, p uint8) (hi, lo uint64) {
+	hi = uint64(decimal128ExponentBias-int(p)) << 49
+	if v < 0 {
+		// negation of math.MinInt64 overflows, but its bits are still the right magnitude
+		v = -v
+		hi |= decimal128SignBit
+	}
+	return hi, uint64(v)
+}
+
+// FixedPointDecimalUintToDecimal128 is FixedPointDecimalToDecimal128 for uint64.
+func FixedPointDecimalUintToDecimal128(v uint64, p uint8) (hi, lo uint64) {
+	return uint64(decimal128ExponentBias-int(p)) << 49, v
+}
+
+// FixedPointDecimalWideToDecimal128 is FixedPointDecimalToDecimal128 for 128 bits.
+// Coefficient of decimal128 is at most 34 digits, so trailing zeros of larger values are dropped to exponent.
+// Returns error when value has more than 34 significant digits.
+func FixedPointDecimalWideToDecimal128(hi int64, lo uint64, p uint8) (dhi, dlo uint64, err error) {
+	uhi, ulo, neg := absWide(hi, lo)
+
+	e := -int(p)
+	for lessWide(decimal128MaxCoeffHi, decimal128MaxCoeffLo, uhi, ulo) {
+		qhi, qlo, r := quoRemWide(uhi, ulo, 10)
+		if r != 0 {
+			return 0, 0, errDecimal128Digits
+		}
+		uhi, ulo, e = qhi, qlo, e+1
+	}
+
+	dhi = uint64(decimal128ExponentBias+e)<<49 | uhi
+	if neg {
+		dhi |= decimal128SignBit
+	}
+	return dhi, ulo, nil
+}
+
+// FixedPointDecimalFromDecimal128 converts IEEE 754-2008 decimal128 in BID encoding to fixed-point decimal of p fractions.
+// Non-canonical values are zero, as required by IEEE 754-2008.
+// Returns error for infinity, NaN, values that do not fit int64, or have more than p fractions.
+func FixedPointDecimalFromDecimal128(hi, lo uint64, p uint8) (int64, error) {
+	uhi, u, neg, err := fixedPointDecimalFromDecimal128(hi, lo, p)
+	if err != nil {
+		return 0, err
+	}
+	if uhi != 0 {
+		return 0, errOverflow
+	}
+	return signed(u, neg)
+}
+
+// FixedPointDecimalUintFromDecimal128 is FixedPointDecimalFromDecimal128 for uint64, negative value is error.
+func FixedPointDecimalUintFromDecimal128(hi, lo uint64, p uint8) (uint64, error) {
+	uhi, u, neg, err := fixedPointDecimalFromDecimal128(hi, lo, p)
+	if err != nil {
+		return 0, err
+	}
+	if uhi != 0 {
+		return 0, errOverflow
+	}
+	return unsigned(u, neg)
+}
+
+// FixedPointDecimalWideFromDecimal128 is FixedPointDecimalFromDecimal128 for 128 bits.
+func FixedPointDecimalWideFromDecimal128(hi, lo uint64, p uint8) (whi int64, wlo uint64, err error) {
+	uhi, ulo, neg, err := fixedPointDecimalFromDecimal128(hi, lo, p)
+	if err != nil {
+		return 0, 0, err
+	}
+	return signedWide(uhi, ulo, neg)
+}
+
+// fixedPointDecimalFromDecimal128 returns 128-bit magnitude and sign of value.
+func fixedPointDecimalFromDecimal128(hi, lo uint64, p uint8) (uhi, ulo uint64, neg bool, err error) {
+	neg = hi&decimal128SignBit != 0
+
+	if (hi>>61)&0b11 == 0b11 {
+		switch (hi >> 58) & 0b11111 {
+		case 0b11110:
+			return 0, 0, false, errDecimal128Inf
+		case 0b11111:
+			return 0, 0, false, errDecimal128NaN
+		}
+		// coefficient is at least 2^113, which is non-canonical
+		return 0, 0, false, nil
+	}
+
+	e := int((hi>>49)&decimal128ExponentMask) - decimal128ExponentBias
+	chi := hi & decimal128CoeffHiMask
+
+	if chi > decimal128MaxCoeffHi || (chi == decimal128MaxCoeffHi && lo > decimal128MaxCoeffLo) {
+		return 0, 0, false, nil
+	}
+
+	uhi, ulo, err = scaleExpWide(chi, lo, e, p)
+	return uhi, ulo, neg, err
+}
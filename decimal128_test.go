@@ -0,0 +1,218 @@
+package fpdecimal_test
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/nikolaydubina/fpdecimal"
+)
+
+func TestFixedPointDecimalToDecimal128(t *testing.T) {
+	tests := []struct {
+		v  int64
+		p  uint8
+		hi uint64
+		lo uint64
+	}{
+		{0, 3, 0x303a000000000000, 0},
+		{1, 0, 0x3040000000000000, 1},
+		{1, 3, 0x303a000000000000, 1},
+		{-15, 1, 0xb03e000000000000, 15},
+		{9223372036854775807, 6, 0x3034000000000000, 9223372036854775807},
+		{-9223372036854775808, 6, 0xb034000000000000, 9223372036854775808},
+	}
+	for _, tc := range tests {
+		t.Run(fmt.Sprint(tc.v, tc.p), func(t *testing.T) {
+			hi, lo := fpdecimal.FixedPointDecimalToDecimal128(tc.v, tc.p)
+			if hi != tc.hi || lo != tc.lo {
+				t.Errorf("%#x %#x", hi, lo)
+			}
+
+			v, err := fpdecimal.FixedPointDecimalFromDecimal128(hi, lo, tc.p)
+			if err != nil {
+				t.Error(err)
+			}
+			if v != tc.v {
+				t.Error(v, tc.v)
+			}
+		})
+	}
+}
+
+func TestFixedPointDecimalFromDecimal128(t *testing.T) {
+	tests := []struct {
+		name string
+		hi   uint64
+		lo   uint64
+		v    int64
+	}{
+		{"1", 0x3040000000000000, 1, 1000},
+		{"-1.5", 0xb03e000000000000, 15, -1500},
+		{"1.500", 0x303a000000000000, 1500, 1500},
+		{"1.50000", 0x3036000000000000, 150000, 1500},
+		{"1E+3", 0x3046000000000000, 1, 1000000},
+		{"0E+6111", 0x5ffe000000000000, 0, 0},
+		{"-0", 0xb040000000000000, 0, 0},
+		{"100000000000000000000E-20", 0x3018000000000005, 0x6bc75e2d63100000, 1000},
+		{"non-canonical coefficient", 0x3041ed09bead87c0, 0x378d8e6400000000, 0},
+		{"non-canonical large form", 0x6000000000000000, 1, 0},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			v, err := fpdecimal.FixedPointDecimalFromDecimal128(tc.hi, tc.lo, 3)
+			if err != nil {
+				t.Error(err)
+			}
+			if v != tc.v {
+				t.Error(v, tc.v)
+			}
+		})
+	}
+}
+
+func TestFixedPointDecimalFromDecimal128_Error(t *testing.T) {
+	tests := []struct {
+		name string
+		hi   uint64
+		lo   uint64
+		err  string
+	}{
+		{"Infinity", 0x7800000000000000, 0, "decimal128: infinity"},
+		{"-Infinity", 0xf800000000000000, 0, "decimal128: infinity"},
+		{"NaN", 0x7c00000000000000, 0, "decimal128: NaN"},
+		{"sNaN", 0x7e00000000000000, 0, "decimal128: NaN"},
+		{"0.0001", 0x3038000000000000, 1, "more fraction digits than supported"},
+		{"1E+16", 0x3060000000000000, 1, "overflow"},
+		{"9223372036854775.808", 0x303a000000000000, 9223372036854775808, "overflow"},
+		{"100000000000000000001E-20", 0x3018000000000005, 0x6bc75e2d63100001, "more fraction digits than supported"},
+		{"10000000000000000000000", 0x304000000000021e, 0x19e0c9bab2400000, "overflow"},
+		{"1E+6111", 0x5ffe000000000000, 1, "overflow"},
+		{"1E-6176", 0, 1, "more fraction digits than supported"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			v, err := fpdecimal.FixedPointDecimalFromDecimal128(tc.hi, tc.lo, 3)
+			if err == nil || err.Error() != tc.err {
+				t.Error(err)
+			}
+			if v != 0 {
+				t.Error(v)
+			}
+		})
+	}
+}
+
+func TestFixedPointDecimalUintDecimal128(t *testing.T) {
+	tests := []struct {
+		v      uint64
+		hi, lo uint64
+	}{
+		{0, 0x303a000000000000, 0},
+		{1500, 0x303a000000000000, 1500},
+		{18446744073709551615, 0x303a000000000000, 18446744073709551615},
+	}
+	for _, tc := range tests {
+		t.Run(fmt.Sprint(tc.v), func(t *testing.T) {
+			if hi, lo := fpdecimal.FixedPointDecimalUintToDecimal128(tc.v, 3); hi != tc.hi || lo != tc.lo {
+				t.Errorf("%#x %#x", hi, lo)
+			}
+			if v, err := fpdecimal.FixedPointDecimalUintFromDecimal128(tc.hi, tc.lo, 3); err != nil || v != tc.v {
+				t.Error(v, err)
+			}
+		})
+	}
+
+	// 18446744073709551615000E-6
+	if v, err := fpdecimal.FixedPointDecimalUintFromDecimal128(0x3034000000000000|0x3e7, 0xfffffffffffffc18, 3); err != nil || v != 18446744073709551615 {
+		t.Error(v, err)
+	}
+
+	errs := []struct {
+		name   string
+		hi, lo uint64
+		err    string
+	}{
+		{"-1.5", 0xb03e000000000000, 15, "negative value"},
+		{"18446744073709551616E-3", 0x303a000000000001, 0, "overflow"},
+		{"NaN", 0x7c00000000000000, 0, "decimal128: NaN"},
+	}
+	for _, tc := range errs {
+		t.Run(tc.name, func(t *testing.T) {
+			if v, err := fpdecimal.FixedPointDecimalUintFromDecimal128(tc.hi, tc.lo, 3); err == nil || err.Error() != tc.err || v != 0 {
+				t.Error(v, err)
+			}
+		})
+	}
+}
+
+func FuzzFixedPointDecimalFromDecimal128(f *testing.F) {
+	f.Add(uint64(0x3040000000000000), uint64(1))
+	f.Add(uint64(0x3018000000000005), uint64(0x6bc75e2d63100000))
+	f.Add(uint64(0x7c00000000000000), uint64(0))
+	f.Fuzz(func(t *testing.T, hi, lo uint64) {
+		v, err := fpdecimal.FixedPointDecimalFromDecimal128(hi, lo, 3)
+		if err != nil {
+			if v != 0 {
+				t.Error("has to be 0 on error")
+			}
+			return
+		}
+
+		qhi, qlo := fpdecimal.FixedPointDecimalToDecimal128(v, 3)
+		q, err := fpdecimal.FixedPointDecimalFromDecimal128(qhi, qlo, 3)
+		if err != nil || q != v {
+			t.Error(v, q, err)
+		}
+	})
+}
+
+func TestFixedPointDecimalWideDecimal128(t *testing.T) {
+	tests := []struct {
+		name string
+		hi   int64
+		lo   uint64
+		p    uint8
+		dhi  uint64
+		dlo  uint64
+	}{
+		{"1.5", 0, 1500, 3, 0x303a000000000000, 1500},
+		{"-1E-18", -1, math.MaxUint64, 18, 0xb01c000000000000, 1},
+		{"10^34-1", 0x1ed09bead87c0, 0x378d8e63ffffffff, 0, 0x3040000000000000 | 0x1ed09bead87c0, 0x378d8e63ffffffff},
+		{"10^35", 0x13426172c74d82, 0x2b878fe800000000, 0, 0x3044000000000000 | 0x314dc6448d93, 0x38c15b0a00000000},
+		{"-10^35", -0x13426172c74d83, 0xd478701800000000, 0, 0xb044000000000000 | 0x314dc6448d93, 0x38c15b0a00000000},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			dhi, dlo, err := fpdecimal.FixedPointDecimalWideToDecimal128(tc.hi, tc.lo, tc.p)
+			if err != nil || dhi != tc.dhi || dlo != tc.dlo {
+				t.Errorf("%#x %#x %v", dhi, dlo, err)
+			}
+			if hi, lo, err := fpdecimal.FixedPointDecimalWideFromDecimal128(dhi, dlo, tc.p); err != nil || hi != tc.hi || lo != tc.lo {
+				t.Error(hi, lo, err)
+			}
+		})
+	}
+
+	// 10^34+1 has 35 significant digits
+	if dhi, dlo, err := fpdecimal.FixedPointDecimalWideToDecimal128(0x1ed09bead87c0, 0x378d8e6400000001, 0); err == nil || err.Error() != "decimal128: more than 34 digits" || dhi != 0 || dlo != 0 {
+		t.Error(dhi, dlo, err)
+	}
+
+	errs := []struct {
+		name   string
+		hi, lo uint64
+		err    string
+	}{
+		{"1E+21", 0x306a000000000000, 1, "overflow"},
+		{"1E-19", 0x301a000000000000, 1, "more fraction digits than supported"},
+		{"Infinity", 0x7800000000000000, 0, "decimal128: infinity"},
+	}
+	for _, tc := range errs {
+		t.Run(tc.name, func(t *testing.T) {
+			if hi, lo, err := fpdecimal.FixedPointDecimalWideFromDecimal128(tc.hi, tc.lo, 18); err == nil || err.Error() != tc.err || hi != 0 || lo != 0 {
+				t.Error(hi, lo, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package fpdecimal
+
+import (
+	"math"
+	"strconv"
+)
+
+var errNotFinite = &errorString{"not finite"}
+
+// FloatToFixedPointDecimal converts float of bitSize into fixed-point decimal of p fractions, rounded by mode.
+// Float is taken as shortest decimal that converts back to it, as in strconv.FormatFloat(f, 'e', -1, bitSize).
+// This way 0.29 is 0.29 and not 0.28999999999999998 that is truncated to 0.289.
+// NaN, Inf and values out of range are error.
+func FloatToFixedPointDecimal(f float64, bitSize int, p uint8, mode RoundingMode) (int64, error) {
+	hi, u, neg, err := floatToFixedPointDecimal(f, bitSize, p, mode)
+	if err != nil {
+		return 0, err
+	}
+	if hi != 0 {
+		return 0, errOverflow
+	}
+	return signed(u, neg)
+}
+
+// FloatToFixedPointDecimalUint is FloatToFixedPointDecimal for uint64, negative value is error.
+// Negative value that rounds to zero is zero.
+func FloatToFixedPointDecimalUint(f float64, bitSize int, p uint8, mode RoundingMode) (uint64, error) {
+	hi, u, neg, err := floatToFixedPointDecimal(f, bitSize, p, mode)
+	if err != nil {
+		return 0, err
+	}
+	if hi != 0 {
+		return 0, errOverflow
+	}
+	return unsigned(u, neg)
+}
+
+// FloatToFixedPointDecimalWide is FloatToFixedPointDecimal for two's complement of high and low bits.
+func FloatToFixedPointDecimalWide(f float64, bitSize int, p uint8, mode RoundingMode) (hi int64, lo uint64, err error) {
+	uhi, ulo, neg, err := floatToFixedPointDecimal(f, bitSize, p, mode)
+	if err != nil {
+		return 0, 0, err
+	}
+	return signedWide(uhi, ulo, neg)
+}
+
+// floatToFixedPointDecimal returns 128-bit magnitude and sign of rounded value.
+func floatToFixedPointDecimal(f float64, bitSize int, p uint8, mode RoundingMode) (hi, lo uint64, neg bool, err error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0, 0, false, errNotFinite
+	}
+	if f == 0 {
+		return 0, 0, false, nil
+	}
+
+	// d.ddde±dd of at most 17 digits fits buffer, so it does not allocate
+	var buf [32]byte
+	b := strconv.AppendFloat(buf[:0], f, 'e', -1, bitSize)
+
+	neg = b[0] == '-'
+	if neg {
+		b = b[1:]
+	}
+
+	var m uint64 // digits
+	var n int    // number of digits
+	i := 0
+	for ; b[i] != 'e'; i++ {
+		if b[i] != sep {
+			m = m*10 + uint64(b[i]-'0')
+			n++
+		}
+	}
+
+	e := 0
+	for _, ch := range b[i+2:] {
+		e = e*10 + int(ch-'0')
+	}
+	if b[i+1] == '-' {
+		e = -e
+	}
+
+	// value is m×10^k of p fractions
+	k := e - (n - 1) + int(p)
+	if k >= 0 {
+		hi, lo, err = scaleExpWide(0, m, k, 0)
+		return hi, lo, neg, err
+	}
+
+	var q, r, d uint64
+	if -k < len(pow10) {
+		d = uint64(pow10[-k])
+		q, r = m/d, m%d
+	} else {
+		// m has less digits than 10^-k, so it is less than half of it
+		q, r, d = 0, 1, math.MaxUint64
+	}
+	if mode.roundUp(q, r, d, neg) {
+		q++
+	}
+	return 0, q, neg, nil
+}
@@ -0,0 +1,203 @@
+package fpdecimal_test
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"testing"
+
+	"github.com/nikolaydubina/fpdecimal"
+)
+
+func TestFloatToFixedPointDecimal(t *testing.T) {
+	tests := []struct {
+		f       float64
+		bitSize int
+		p       uint8
+		mode    fpdecimal.RoundingMode
+		v       int64
+	}{
+		{0, 64, 3, fpdecimal.ToNearestEven, 0},
+		{math.Copysign(0, -1), 64, 3, fpdecimal.ToNegativeInf, 0},
+		{0.29, 64, 3, fpdecimal.ToZero, 290},
+		{-0.29, 64, 3, fpdecimal.ToZero, -290},
+		{0.30000000000000004, 64, 3, fpdecimal.ToPositiveInf, 301},
+		{0.30000000000000004, 64, 3, fpdecimal.ToNearestEven, 300},
+		{float64(float32(0.29)), 32, 3, fpdecimal.ToZero, 290},
+		{float64(float32(0.29)), 64, 3, fpdecimal.ToZero, 289},
+		{0.0005, 64, 3, fpdecimal.ToNearestEven, 0},
+		{0.0015, 64, 3, fpdecimal.ToNearestEven, 2},
+		{0.0005, 64, 3, fpdecimal.ToNearestAway, 1},
+		{-0.0005, 64, 3, fpdecimal.ToNearestAway, -1},
+		{-0.0005, 64, 3, fpdecimal.ToPositiveInf, 0},
+		{1e-30, 64, 3, fpdecimal.ToPositiveInf, 1},
+		{1e-30, 64, 3, fpdecimal.ToNearestEven, 0},
+		{-1e-30, 64, 3, fpdecimal.ToNegativeInf, -1},
+		{-1e-30, 64, 3, fpdecimal.AwayFromZero, -1},
+		{123456.789, 64, 0, fpdecimal.ToNearestEven, 123457},
+		{1.5e15, 64, 3, fpdecimal.ToZero, 1_500_000_000_000_000_000},
+		{1 << 62, 64, 0, fpdecimal.ToZero, 4_611_686_018_427_388_000}, // shortest is 4.611686018427388e18
+	}
+	for _, tc := range tests {
+		t.Run(fmt.Sprint(tc.f, tc.bitSize, tc.p, tc.mode), func(t *testing.T) {
+			v, err := fpdecimal.FloatToFixedPointDecimal(tc.f, tc.bitSize, tc.p, tc.mode)
+			if err != nil || v != tc.v {
+				t.Error(v, tc.v, err)
+			}
+		})
+	}
+}
+
+func TestFloatToFixedPointDecimal_Error(t *testing.T) {
+	tests := []struct {
+		f   float64
+		err string
+	}{
+		{math.NaN(), "not finite"},
+		{math.Inf(1), "not finite"},
+		{math.Inf(-1), "not finite"},
+		{1e16, "overflow"},
+		{-1e16, "overflow"},
+		{9223372036854775.807, "overflow"},
+		{1e300, "overflow"},
+	}
+	for _, tc := range tests {
+		t.Run(fmt.Sprint(tc.f), func(t *testing.T) {
+			v, err := fpdecimal.FloatToFixedPointDecimal(tc.f, 64, 3, fpdecimal.ToNearestEven)
+			if err == nil || err.Error() != tc.err {
+				t.Error(err)
+			}
+			if v != 0 {
+				t.Error(v)
+			}
+		})
+	}
+}
+
+func TestFloatToFixedPointDecimalUint(t *testing.T) {
+	tests := []struct {
+		f    float64
+		p    uint8
+		mode fpdecimal.RoundingMode
+		v    uint64
+		err  string
+	}{
+		{0.29, 3, fpdecimal.ToZero, 290, ""},
+		{-0.0005, 3, fpdecimal.ToPositiveInf, 0, ""},
+		{-0.0005, 3, fpdecimal.ToNearestAway, 0, "negative value"},
+		{-1, 3, fpdecimal.ToZero, 0, "negative value"},
+		{1e19, 0, fpdecimal.ToZero, 10_000_000_000_000_000_000, ""},
+		{1.8e16, 3, fpdecimal.ToZero, 18_000_000_000_000_000_000, ""},
+		{1.9e16, 3, fpdecimal.ToZero, 0, "overflow"},
+		{math.NaN(), 3, fpdecimal.ToZero, 0, "not finite"},
+	}
+	for _, tc := range tests {
+		t.Run(fmt.Sprint(tc.f, tc.p, tc.mode), func(t *testing.T) {
+			v, err := fpdecimal.FloatToFixedPointDecimalUint(tc.f, 64, tc.p, tc.mode)
+			if v != tc.v || (err == nil) != (tc.err == "") || (err != nil && err.Error() != tc.err) {
+				t.Error(v, tc.v, err)
+			}
+		})
+	}
+}
+
+func TestFloatToFixedPointDecimalWide(t *testing.T) {
+	tests := []struct {
+		f    float64
+		p    uint8
+		mode fpdecimal.RoundingMode
+		v    string
+		err  string
+	}{
+		{0.29, 18, fpdecimal.ToZero, "0.29", ""},
+		{0.29, 18, fpdecimal.ToNearestEven, "0.29", ""},
+		{-1.5, 18, fpdecimal.ToZero, "-1.5", ""},
+		{1e20, 18, fpdecimal.ToZero, "100000000000000000000", ""},
+		{-1.7e20, 18, fpdecimal.ToZero, "-170000000000000000000", ""},
+		{1.8e20, 18, fpdecimal.ToZero, "", "overflow"},
+		{math.MaxFloat64, 18, fpdecimal.ToZero, "", "overflow"},
+		{math.Inf(-1), 18, fpdecimal.ToZero, "", "not finite"},
+	}
+	for _, tc := range tests {
+		t.Run(fmt.Sprint(tc.f, tc.p, tc.mode), func(t *testing.T) {
+			hi, lo, err := fpdecimal.FloatToFixedPointDecimalWide(tc.f, 64, tc.p, tc.mode)
+			if tc.err != "" {
+				if err == nil || err.Error() != tc.err || hi != 0 || lo != 0 {
+					t.Error(hi, lo, err)
+				}
+				return
+			}
+			if v := fpdecimal.FixedPointDecimalWideToString(hi, lo, tc.p); err != nil || v != tc.v {
+				t.Error(v, tc.v, err)
+			}
+		})
+	}
+}
+
+func TestFloatToFixedPointDecimal_NoAlloc(t *testing.T) {
+	var v int64
+	n := testing.AllocsPerRun(100, func() {
+		v, _ = fpdecimal.FloatToFixedPointDecimal(-1.2345678901234567e-5, 64, 18, fpdecimal.ToNearestEven)
+	})
+	if n != 0 || v != -12345678901235 {
+		t.Error(n, v)
+	}
+}
+
+func FuzzFloatToFixedPointDecimal(f *testing.F) {
+	tests := []float64{0, 0.29, 0.0005, 0.0015, 1e-30, 123456.789, 1e15, math.MaxFloat64, math.SmallestNonzeroFloat64}
+	for _, tc := range tests {
+		for _, p := range []uint8{0, 3, 18} {
+			f.Add(tc, p)
+			f.Add(-tc, p)
+		}
+	}
+	f.Fuzz(func(t *testing.T, v float64, p uint8) {
+		if p > 18 || math.IsNaN(v) || math.IsInf(v, 0) {
+			t.Skip()
+		}
+
+		// shortest decimal scaled to p fractions, and its truncation
+		x, _ := new(big.Rat).SetString(strconv.FormatFloat(v, 'e', -1, 64))
+		x.Mul(x, new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(p)), nil)))
+		q := new(big.Int).Quo(x.Num(), x.Denom())
+
+		for _, mode := range roundingModes {
+			s, err := fpdecimal.FloatToFixedPointDecimal(v, 64, p, mode)
+			if err != nil {
+				if err.Error() != "overflow" || s != 0 {
+					t.Error(v, p, mode, s, err)
+				}
+				// overflow is only when nearest integers do not fit
+				if d := new(big.Int).Abs(q); d.Cmp(big.NewInt(math.MaxInt64-1)) < 0 {
+					t.Error(v, p, mode, q)
+				}
+				continue
+			}
+
+			// result is less than 1 from value, and at most 0.5 when rounding to nearest
+			diff := new(big.Rat).Sub(new(big.Rat).SetInt64(s), x)
+			diff.Abs(diff)
+			nearest := mode == fpdecimal.ToNearestEven || mode == fpdecimal.ToNearestAway
+			if diff.Cmp(big.NewRat(1, 1)) >= 0 || (nearest && diff.Cmp(big.NewRat(1, 2)) > 0) {
+				t.Error(v, p, mode, s, x.FloatString(3))
+			}
+
+			if mode == fpdecimal.ToZero && big.NewInt(s).Cmp(q) != 0 {
+				t.Error(v, p, s, q)
+			}
+		}
+	})
+}
+
+func BenchmarkFloatToFixedPointDecimal(b *testing.B) {
+	var v int64
+	var err error
+	for n := 0; n < b.N; n++ {
+		v, err = fpdecimal.FloatToFixedPointDecimal(123.456, 64, 3, fpdecimal.ToNearestEven)
+	}
+	if v != 123456 || err != nil {
+		b.Error(v, err)
+	}
+}
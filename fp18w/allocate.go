@@ -0,0 +1,64 @@
+package fp18w
+
+import (
+	"cmp"
+	"math/bits"
+	"slices"
+)
+
+// allocate distributes magnitude m in multiples of unit u, as fpdecimal.Allocate.
+func allocate(m, u uint128, ratios []int64) []uint128 {
+	var total, carry uint64
+	for _, r := range ratios {
+		if r < 0 {
+			panic("ratio of allocation is negative")
+		}
+		if total, carry = bits.Add64(total, uint64(r), 0); carry != 0 {
+			panic("sum of ratios overflows")
+		}
+	}
+	if total == 0 {
+		panic("sum of ratios is zero")
+	}
+
+	k, rest := divmod192([3]uint64{0, m.hi, m.lo}, u)
+
+	// each ratio is at most total, so quotient fits 128 bits
+	parts := make([]uint128, len(ratios))
+	rems := make([]uint64, len(ratios))
+	left, largest := k, 0
+	for i, r := range ratios {
+		p := mul64(k, uint64(r))
+		rems[i] = div64(p[:], total)
+		parts[i] = uint128{p[1], p[2]}
+		var b uint64
+		left.lo, b = bits.Sub64(left.lo, parts[i].lo, 0)
+		left.hi, _ = bits.Sub64(left.hi, parts[i].hi, b)
+		if r > ratios[largest] {
+			largest = i
+		}
+	}
+
+	// units left are less than number of parts
+	if left.lo > 0 {
+		order := make([]int, len(ratios))
+		for i := range order {
+			order[i] = i
+		}
+		slices.SortStableFunc(order, func(a, b int) int { return cmp.Compare(rems[b], rems[a]) })
+		for _, i := range order[:left.lo] {
+			parts[i].lo, carry = bits.Add64(parts[i].lo, 1, 0)
+			parts[i].hi += carry
+		}
+	}
+
+	for i := range parts {
+		p := mul128(parts[i], u)
+		parts[i] = uint128{p[2], p[3]}
+		if i == largest {
+			parts[i].lo, carry = bits.Add64(parts[i].lo, rest.lo, 0)
+			parts[i].hi += rest.hi + carry
+		}
+	}
+	return parts
+}
@@ -0,0 +1,33 @@
+package fp18w
+
+import (
+	"github.com/nikolaydubina/fpdecimal"
+	"github.com/nikolaydubina/fpdecimal/fp6"
+)
+
+// fp6 has 12 less fractional digits
+const fp6Multiplier = 1_000_000_000_000
+
+// FromFP6 returns same value with 18 fractional digits, range of fp18w is larger.
+func FromFP6(v fp6.Decimal) Decimal {
+	s := v.Scaled()
+	if s < 0 {
+		return fromAbs(uint128{0, uint64(-s)}.mul(fp6Multiplier), true)
+	}
+	return fromAbs(uint128{0, uint64(s)}.mul(fp6Multiplier), false)
+}
+
+// FP6 rounds to 6 fractional digits.
+// Returns error when value does not fit, since range of fp6 is smaller.
+func (a Decimal) FP6(mode fpdecimal.RoundingMode) (fp6.Decimal, error) {
+	v, err := fpdecimal.FixedPointDecimalFromWide(a.hi, a.lo, fractionDigits, 6, mode)
+	return fp6.FromIntScaled(v), err
+}
+
+// MulFP6 multiplies by value with 6 fractional digits, such as amount by price, and rounds result.
+// Returns error when result does not fit.
+func (a Decimal) MulFP6(b fp6.Decimal, mode fpdecimal.RoundingMode) (Decimal, error) {
+	s := b.Scaled()
+	hi, lo, err := fpdecimal.MulDivRoundWide(a.hi, a.lo, s>>63, uint64(s), 0, 1_000_000, mode)
+	return Decimal{hi: hi, lo: lo}, err
+}
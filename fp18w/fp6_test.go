@@ -0,0 +1,91 @@
+package fp18w_test
+
+import (
+	"testing"
+
+	"github.com/nikolaydubina/fpdecimal"
+	fp "github.com/nikolaydubina/fpdecimal/fp18w"
+	"github.com/nikolaydubina/fpdecimal/fp6"
+)
+
+func TestFromFP6(t *testing.T) {
+	tests := []string{
+		"0",
+		"1.000001",
+		"-1.5",
+		"9223372036854.775807",
+		"-9223372036854.775808",
+	}
+	for _, tc := range tests {
+		t.Run(tc, func(t *testing.T) {
+			v, _ := fp6.FromString(tc)
+			if s := fp.FromFP6(v).String(); s != tc {
+				t.Error(s, tc)
+			}
+		})
+	}
+}
+
+func TestDecimal_FP6(t *testing.T) {
+	tests := []struct {
+		v    string
+		mode fpdecimal.RoundingMode
+		s    string
+	}{
+		{"1.0000005", fpdecimal.ToNearestEven, "1"},
+		{"1.0000015", fpdecimal.ToNearestEven, "1.000002"},
+		{"1.0000005", fpdecimal.ToNearestAway, "1.000001"},
+		{"-1.000000999999999999", fpdecimal.ToZero, "-1"},
+		{"-1.000000000000000001", fpdecimal.AwayFromZero, "-1.000001"},
+		{"-1.000000000000000001", fpdecimal.ToNegativeInf, "-1.000001"},
+		{"-1.000000000000000001", fpdecimal.ToPositiveInf, "-1"},
+		{"-9223372036854.775808", fpdecimal.ToNearestEven, "-9223372036854.775808"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.v+" "+tc.mode.String(), func(t *testing.T) {
+			v, _ := fp.FromString(tc.v)
+			q, err := v.FP6(tc.mode)
+			if err != nil || q.String() != tc.s {
+				t.Error(q, err, tc.s)
+			}
+		})
+	}
+
+	v, _ := fp.FromString("9223372036854.7758075")
+	if q, err := v.FP6(fpdecimal.ToNearestEven); err == nil || q != fp6.Zero {
+		t.Error(q, err)
+	}
+}
+
+func TestDecimal_MulFP6(t *testing.T) {
+	tests := []struct {
+		amount string
+		price  string
+		mode   fpdecimal.RoundingMode
+		s      string
+	}{
+		{"100", "1.234567", fpdecimal.ToNearestEven, "123.4567"},
+		{"0.000000000000000001", "0.5", fpdecimal.ToNearestEven, "0"},
+		{"0.000000000000000001", "0.5", fpdecimal.ToNearestAway, "0.000000000000000001"},
+		{"-2.5", "0.000001", fpdecimal.ToZero, "-0.0000025"},
+		{"100000000000000000000", "1.700000", fpdecimal.ToNearestEven, "170000000000000000000"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.amount+"*"+tc.price, func(t *testing.T) {
+			a, _ := fp.FromString(tc.amount)
+			p, _ := fp6.FromString(tc.price)
+			v, err := a.MulFP6(p, tc.mode)
+			if err != nil {
+				t.Error(err)
+			}
+			if s := v.String(); s != tc.s {
+				t.Error(s, tc.s)
+			}
+		})
+	}
+
+	v, err := fp.FromInt(int64(100_000_000_000_000_000)).MulFP6(fp6.FromInt(2000), fpdecimal.ToZero)
+	if err == nil || v != fp.Zero {
+		t.Error(v, err)
+	}
+}
@@ -0,0 +1,548 @@
+// Package fp18w is fixed-point decimal of 18 fractional digits in 128 bits, for values that do not fit fp6 or fp18.
+// It has same encodings and operations as fp3, and conversion to and from fp6.
+package fp18w
+
+import (
+	"encoding/xml"
+	"flag"
+	"fmt"
+	"io"
+	"iter"
+	"log/slog"
+	"math"
+	"math/big"
+	"math/bits"
+	"os"
+	"strings"
+
+	"github.com/nikolaydubina/fpdecimal"
+)
+
+// Decimal with 18 fractional digits, two's complement of high and low bits.
+// Fractions lower than that are discarded in operations.
+// Max: +170141183460469231731.687303715884105727
+// Min: -170141183460469231731.687303715884105728
+type Decimal struct {
+	hi int64
+	lo uint64
+}
+
+var Zero = Decimal{}
+
+type integer interface {
+	int | int8 | int16 | int32 | int64 | uint | uint8 | uint16 | uint32 | uint64
+}
+
+const (
+	fractionDigits = 18
+	multiplier     = 1_000_000_000_000_000_000
+)
+
+func FromInt[T integer](v T) Decimal {
+	if v < 0 {
+		return fromAbs(uint128{0, uint64(-int64(v))}.mul(multiplier), true)
+	}
+	return fromAbs(uint128{0, uint64(v)}.mul(multiplier), false)
+}
+
+// FromFloat returns zero for NaN, Inf and values out of range.
+func FromFloat[T float32 | float64](v T) Decimal {
+	f := float64(v) * multiplier
+	if math.IsNaN(f) || f >= 1<<127 || f < -(1<<127) {
+		return Zero
+	}
+	neg := f < 0
+	f = math.Abs(f)
+
+	// exact, since float has less bits than lower half of large values
+	hi := math.Floor(f / (1 << 64))
+	lo := f - hi*(1<<64)
+	return fromAbs(uint128{uint64(hi), uint64(lo)}, neg)
+}
+
+// FromFloatRound converts shortest decimal of float, as in strconv.FormatFloat, rounded by mode.
+// Unlike FromFloat, 0.29 is 0.29, and NaN, Inf and values out of range are error.
+func FromFloatRound[T float32 | float64](v T, mode fpdecimal.RoundingMode) (Decimal, error) {
+	bitSize := 64
+	if _, ok := any(v).(float32); ok {
+		bitSize = 32
+	}
+	hi, lo, err := fpdecimal.FloatToFixedPointDecimalWide(float64(v), bitSize, fractionDigits, mode)
+	return Decimal{hi: hi, lo: lo}, err
+}
+
+// FromIntScaled expects value already scaled to minor units
+func FromIntScaled[T integer](v T) Decimal {
+	if v < 0 {
+		return Decimal{hi: -1, lo: uint64(int64(v))}
+	}
+	return Decimal{lo: uint64(v)}
+}
+
+// FromScaled expects high and low bits of two's complement of value already scaled to minor units.
+func FromScaled(hi int64, lo uint64) Decimal { return Decimal{hi: hi, lo: lo} }
+
+func FromString(s string) (Decimal, error) {
+	hi, lo, err := fpdecimal.ParseFixedPointDecimalWide([]byte(s), fractionDigits)
+	return Decimal{hi: hi, lo: lo}, err
+}
+
+// FromEnv decodes environment variable as text, or returns default value when it is not set or empty.
+func FromEnv(name string, value Decimal) (Decimal, error) {
+	s, ok := os.LookupEnv(name)
+	if !ok || strings.TrimSpace(s) == "" {
+		return value, nil
+	}
+	if err := value.UnmarshalText([]byte(s)); err != nil {
+		return Zero, fmt.Errorf("env %s: %w", name, err)
+	}
+	return value, nil
+}
+
+func (v *Decimal) UnmarshalJSON(b []byte) (err error) {
+	v.hi, v.lo, err = fpdecimal.ParseFixedPointDecimalWide(b, fractionDigits)
+	return err
+}
+
+func (v Decimal) MarshalJSON() ([]byte, error) { return v.Append(nil), nil }
+
+// DecodeArray decodes JSON array of numbers, reading data in chunks.
+// This is faster than encoding/json for large arrays.
+func DecodeArray(r io.Reader) iter.Seq2[Decimal, error] {
+	return fpdecimal.DecodeJSONArrayFunc(r, func(s []byte) (Decimal, error) {
+		hi, lo, err := fpdecimal.ParseFixedPointDecimalWide(s, fractionDigits)
+		return Decimal{hi: hi, lo: lo}, err
+	})
+}
+
+// AppendArray appends JSON array of numbers.
+func AppendArray(b []byte, vs []Decimal) []byte {
+	b = append(b, '[')
+	for i, v := range vs {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		b = v.Append(b)
+	}
+	return append(b, ']')
+}
+
+// UnmarshalText decodes text, surrounding whitespace and quotes are ignored, underscores can separate digits.
+// This includes XML element values and attributes, and values of configuration files.
+func (v *Decimal) UnmarshalText(b []byte) (err error) {
+	v.hi, v.lo, err = fpdecimal.ParseFixedPointDecimalWideText(b, fractionDigits)
+	return err
+}
+
+func (v Decimal) MarshalText() ([]byte, error) { return v.Append(nil), nil }
+
+// UnmarshalXMLAttr decodes attribute value, surrounding whitespace is ignored.
+func (v *Decimal) UnmarshalXMLAttr(attr xml.Attr) error { return v.UnmarshalText([]byte(attr.Value)) }
+
+func (v Decimal) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	return xml.Attr{Name: name, Value: v.String()}, nil
+}
+
+// Set decodes command-line flag, as in flag.Value.
+func (v *Decimal) Set(s string) error { return v.UnmarshalText([]byte(s)) }
+
+// Type is name of command-line flag type, as in github.com/spf13/pflag.
+func (v Decimal) Type() string { return "decimal" }
+
+// Flag defines command-line flag, as flag.Float64.
+// For flag.FlagSet use flag.FlagSet.Var, since *Decimal is flag.Value.
+func Flag(name string, value Decimal, usage string) *Decimal {
+	p := new(Decimal)
+	FlagVar(p, name, value, usage)
+	return p
+}
+
+// FlagVar defines command-line flag, as flag.Float64Var.
+func FlagVar(p *Decimal, name string, value Decimal, usage string) {
+	*p = value
+	flag.Var(p, name, usage)
+}
+
+// LogValue is number in slog.JSONHandler and decimal string in slog.TextHandler, as slog.LogValuer.
+func (a Decimal) LogValue() slog.Value { return slog.AnyValue(logValue(a)) }
+
+// Attr returns slog.Attr, as slog.Float64.
+func Attr(key string, v Decimal) slog.Attr { return slog.Attr{Key: key, Value: v.LogValue()} }
+
+// logValue does not implement slog.LogValuer, so that slog does not resolve it again.
+type logValue Decimal
+
+func (v logValue) MarshalJSON() ([]byte, error) { return Decimal(v).Append(nil), nil }
+
+func (v logValue) MarshalText() ([]byte, error) { return Decimal(v).Append(nil), nil }
+
+// AppendCBOR appends CBOR decimal fraction (tag 4), mantissa beyond 64 bits is bignum (tag 2 or 3).
+func (a Decimal) AppendCBOR(b []byte) []byte {
+	return fpdecimal.AppendCBORDecimalFractionWide(b, a.hi, a.lo, fractionDigits)
+}
+
+func (v *Decimal) UnmarshalCBOR(b []byte) (err error) {
+	v.hi, v.lo, err = fpdecimal.ParseCBORDecimalFractionWide(b, fractionDigits)
+	return err
+}
+
+func (v Decimal) MarshalCBOR() ([]byte, error) { return v.AppendCBOR(nil), nil }
+
+// AppendMsgpack appends MessagePack extension fpdecimal.MsgpackExtDecimal with 16 bytes of mantissa.
+func (a Decimal) AppendMsgpack(b []byte) []byte {
+	return fpdecimal.AppendMsgpackDecimalWide(b, a.hi, a.lo, fractionDigits)
+}
+
+func (v *Decimal) UnmarshalMsgpack(b []byte) (err error) {
+	v.hi, v.lo, err = fpdecimal.ParseMsgpackDecimalWide(b, fractionDigits)
+	return err
+}
+
+func (v Decimal) MarshalMsgpack() ([]byte, error) { return v.AppendMsgpack(nil), nil }
+
+// Decimal128 returns high and low bits of IEEE 754-2008 decimal128, as in BSON Decimal128.
+// Returns error when value has more than 34 significant digits, which is precision of decimal128.
+func (a Decimal) Decimal128() (hi, lo uint64, err error) {
+	return fpdecimal.FixedPointDecimalWideToDecimal128(a.hi, a.lo, fractionDigits)
+}
+
+// FromDecimal128 expects high and low bits of IEEE 754-2008 decimal128, as in BSON Decimal128.
+// Returns error when value does not fit or has more fractional digits.
+func FromDecimal128(hi, lo uint64) (Decimal, error) {
+	vhi, vlo, err := fpdecimal.FixedPointDecimalWideFromDecimal128(hi, lo, fractionDigits)
+	return Decimal{hi: vhi, lo: vlo}, err
+}
+
+// Unscaled returns high and low bits of two's complement of unscaled value of DECIMAL(precision, scale),
+// as in Apache Arrow Decimal128 and Parquet.
+// Returns error when value has more fractional digits than scale or more digits than precision.
+func (a Decimal) Unscaled(precision, scale uint8) (hi int64, lo uint64, err error) {
+	return fpdecimal.FixedPointDecimalWideToUnscaled(a.hi, a.lo, fractionDigits, precision, scale)
+}
+
+// FromUnscaled expects high and low bits of two's complement of unscaled value of DECIMAL(precision, scale),
+// as in Apache Arrow Decimal128 and Parquet.
+func FromUnscaled(hi int64, lo uint64, precision, scale uint8) (Decimal, error) {
+	vhi, vlo, err := fpdecimal.FixedPointDecimalWideFromUnscaled(hi, lo, precision, scale, fractionDigits)
+	return Decimal{hi: vhi, lo: vlo}, err
+}
+
+// AppendUnscaledBytes appends unscaled value of DECIMAL(precision, scale) as big-endian two's complement of size bytes.
+// This is Parquet FIXED_LEN_BYTE_ARRAY and Avro fixed, or when size is 0 then Parquet BYTE_ARRAY and Avro bytes.
+func (a Decimal) AppendUnscaledBytes(b []byte, size int, precision, scale uint8) ([]byte, error) {
+	hi, lo, err := a.Unscaled(precision, scale)
+	if err != nil {
+		return b, err
+	}
+	return fpdecimal.AppendTwosComplementWide(b, hi, lo, size)
+}
+
+// FromUnscaledBytes expects unscaled value of DECIMAL(precision, scale) as big-endian two's complement.
+func FromUnscaledBytes(b []byte, precision, scale uint8) (Decimal, error) {
+	hi, lo, err := fpdecimal.ParseTwosComplementWide(b)
+	if err != nil {
+		return Zero, err
+	}
+	return FromUnscaled(hi, lo, precision, scale)
+}
+
+// AppendPostgresNumeric appends PostgreSQL NUMERIC binary format.
+// This can be used in binary encoding of custom types in PostgreSQL drivers.
+func (a Decimal) AppendPostgresNumeric(b []byte) []byte {
+	return fpdecimal.AppendPostgresNumericWide(b, a.hi, a.lo, fractionDigits)
+}
+
+// FromPostgresNumeric expects PostgreSQL NUMERIC binary format.
+// Returns error for NaN, infinity, and values that do not fit or have more fractional digits.
+func FromPostgresNumeric(b []byte) (Decimal, error) {
+	hi, lo, err := fpdecimal.ParsePostgresNumericWide(b, fractionDigits)
+	return Decimal{hi: hi, lo: lo}, err
+}
+
+// BigRat returns exact value.
+func (a Decimal) BigRat() *big.Rat { return new(big.Rat).SetFrac(a.BigInt(), big.NewInt(multiplier)) }
+
+// BigInt returns value scaled to minor units, as Scaled.
+func (a Decimal) BigInt() *big.Int {
+	u, neg := a.abs()
+	v := new(big.Int).SetUint64(u.hi)
+	v.Lsh(v, 64).Or(v, new(big.Int).SetUint64(u.lo))
+	if neg {
+		v.Neg(v)
+	}
+	return v
+}
+
+// BigFloat returns value rounded to 64 bits of mantissa or more, as big.Float.SetRat.
+func (a Decimal) BigFloat() *big.Float { return new(big.Float).SetRat(a.BigRat()) }
+
+// FromBigRat converts rational rounded by mode, value out of range is error.
+func FromBigRat(r *big.Rat, mode fpdecimal.RoundingMode) (Decimal, error) {
+	hi, lo, err := fpdecimal.BigRatToFixedPointDecimalWide(r, fractionDigits, mode)
+	return Decimal{hi: hi, lo: lo}, err
+}
+
+// FromBigInt expects value already scaled to minor units, as BigInt.
+func FromBigInt(v *big.Int) (Decimal, error) {
+	return FromBigRat(new(big.Rat).SetFrac(v, big.NewInt(multiplier)), fpdecimal.ToZero)
+}
+
+// FromBigFloat converts float rounded by mode, Inf and value out of range is error.
+func FromBigFloat(f *big.Float, mode fpdecimal.RoundingMode) (Decimal, error) {
+	hi, lo, err := fpdecimal.BigFloatToFixedPointDecimalWide(f, fractionDigits, mode)
+	return Decimal{hi: hi, lo: lo}, err
+}
+
+// Scaled returns high and low bits of two's complement of value scaled to minor units.
+func (a Decimal) Scaled() (hi int64, lo uint64) { return a.hi, a.lo }
+
+// IntPart returns whole units, fractions are truncated toward zero.
+// Whole units beyond range of int64 wrap around as int64 does.
+func (a Decimal) IntPart() int64 {
+	whole, _ := a.Split()
+	return whole
+}
+
+// FracPart returns fractions, with same sign as value.
+func (a Decimal) FracPart() Decimal {
+	_, frac := a.Split()
+	return FromIntScaled(frac)
+}
+
+// Split returns whole units and fractions in minor units, both with same sign as value.
+// Whole units beyond range of int64 wrap around as int64 does.
+func (a Decimal) Split() (whole int64, frac int64) {
+	u, neg := a.abs()
+	w := []uint64{u.hi, u.lo}
+	r := div64(w, multiplier)
+	if neg {
+		return -int64(w[1]), -int64(r)
+	}
+	return int64(w[1]), int64(r)
+}
+
+// ToInt returns whole units rounded by mode, value out of range of int64 is error.
+func (a Decimal) ToInt(mode fpdecimal.RoundingMode) (int64, error) {
+	return fpdecimal.FixedPointDecimalFromWide(a.hi, a.lo, fractionDigits, 0, mode)
+}
+
+func (a Decimal) Float32() float32 { return float32(a.Float64()) }
+
+func (a Decimal) Float64() float64 {
+	u, neg := a.abs()
+	f := (float64(u.hi)*(1<<64) + float64(u.lo)) / multiplier
+	if neg {
+		return -f
+	}
+	return f
+}
+
+// Append appends formatted value to destination buffer.
+func (a Decimal) Append(b []byte) []byte {
+	return fpdecimal.AppendFixedPointDecimalWide(b, a.hi, a.lo, fractionDigits)
+}
+
+func (a Decimal) String() string {
+	return fpdecimal.FixedPointDecimalWideToString(a.hi, a.lo, fractionDigits)
+}
+
+func (a Decimal) Add(b Decimal) Decimal {
+	lo, c := bits.Add64(a.lo, b.lo, 0)
+	return Decimal{hi: a.hi + b.hi + int64(c), lo: lo}
+}
+
+func (a Decimal) Sub(b Decimal) Decimal {
+	lo, c := bits.Sub64(a.lo, b.lo, 0)
+	return Decimal{hi: a.hi - b.hi - int64(c), lo: lo}
+}
+
+func (a Decimal) Mul(b Decimal) Decimal {
+	ua, na := a.abs()
+	ub, nb := b.abs()
+	p := mul128(ua, ub)
+	div64(p[:], multiplier)
+	return fromAbs(uint128{p[2], p[3]}, na != nb)
+}
+
+func (a Decimal) Div(b Decimal) Decimal {
+	ua, na := a.abs()
+	ub, nb := b.abs()
+	q, _ := divmod192(mul64(ua, multiplier), ub)
+	return fromAbs(q, na != nb)
+}
+
+func (a Decimal) Mod(b Decimal) Decimal {
+	ua, na := a.abs()
+	ub, _ := b.abs()
+	m, _ := divmod192([3]uint64{0, ub.hi, ub.lo}, uint128{0, multiplier})
+	_, r := divmod192([3]uint64{0, ua.hi, ua.lo}, m)
+	return fromAbs(r, na)
+}
+
+func (a Decimal) DivMod(b Decimal) (part, remainder Decimal) { return a.Div(b), a.Mod(b) }
+
+// MulInt multiplies by integer, such as quantity.
+// Unlike Mul, value is not scaled, so it overflows only when result does not fit.
+func (a Decimal) MulInt(n int64) Decimal {
+	u, neg := a.abs()
+	if n < 0 {
+		return fromAbs(u.mul(uint64(-n)), !neg)
+	}
+	return fromAbs(u.mul(uint64(n)), neg)
+}
+
+// MulIntChecked is MulInt that returns error on overflow.
+func (a Decimal) MulIntChecked(n int64) (Decimal, error) {
+	hi, lo, err := fpdecimal.MulDivRoundWide(a.hi, a.lo, n>>63, uint64(n), 0, 1, fpdecimal.ToZero)
+	return Decimal{hi: hi, lo: lo}, err
+}
+
+// DivInt divides by integer, rounded by mode to minor units.
+// Panics when n is zero, as integer division.
+func (a Decimal) DivInt(n int64, mode fpdecimal.RoundingMode) Decimal {
+	if n == 0 {
+		panic("division by zero")
+	}
+	v, err := a.DivIntChecked(n, mode)
+	if err != nil {
+		// only min value by -1 overflows, which wraps around to itself
+		return a
+	}
+	return v
+}
+
+// DivIntChecked is DivInt that returns error on division by zero and overflow.
+func (a Decimal) DivIntChecked(n int64, mode fpdecimal.RoundingMode) (Decimal, error) {
+	hi, lo, err := fpdecimal.MulDivRoundWide(a.hi, a.lo, 0, 1, n>>63, uint64(n), mode)
+	return Decimal{hi: hi, lo: lo}, err
+}
+
+// QuoRemInt returns quotient truncated toward zero to minor units and remainder, such that a = q×n + r.
+// Panics when n is zero, as integer division.
+func (a Decimal) QuoRemInt(n int64) (q, r Decimal) {
+	q = a.DivInt(n, fpdecimal.ToZero)
+	return q, a.Sub(q.MulInt(n))
+}
+
+// QuoRemIntChecked is QuoRemInt that returns error on division by zero and overflow.
+func (a Decimal) QuoRemIntChecked(n int64) (q, r Decimal, err error) {
+	q, err = a.DivIntChecked(n, fpdecimal.ToZero)
+	if err != nil {
+		return Zero, Zero, err
+	}
+	return q, a.Sub(q.MulInt(n)), nil
+}
+
+// Allocate distributes value by ratios, parts sum exactly to value.
+// Minor units left after truncation go to parts of largest remainders, ties to first parts.
+// Panics when ratios are negative or sum to zero.
+func (a Decimal) Allocate(ratios ...int64) []Decimal {
+	return a.AllocateUnit(FromIntScaled(1), ratios...)
+}
+
+// AllocateUnit is Allocate in multiples of unit, such as cents.
+// Fractions of unit go to first part of largest ratio.
+func (a Decimal) AllocateUnit(unit Decimal, ratios ...int64) []Decimal {
+	if unit.Compare(Zero) <= 0 {
+		panic("unit of allocation is not positive")
+	}
+	m, neg := a.abs()
+	u, _ := unit.abs()
+	vs := allocate(m, u, ratios)
+	parts := make([]Decimal, len(vs))
+	for i, v := range vs {
+		parts[i] = fromAbs(v, neg)
+	}
+	return parts
+}
+
+// SplitN splits value into n parts that differ at most by one minor unit, first parts are larger.
+// For multiples of unit, use AllocateUnit with equal ratios.
+// Panics when n is not positive.
+func (a Decimal) SplitN(n int) []Decimal {
+	ratios := make([]int64, max(n, 0))
+	for i := range ratios {
+		ratios[i] = 1
+	}
+	return a.Allocate(ratios...)
+}
+
+// Pow raises value to integer power n, rounded by mode at each multiplication.
+// Negative n is reciprocal of power.
+func (a Decimal) Pow(n int, mode fpdecimal.RoundingMode) (Decimal, error) {
+	hi, lo, err := fpdecimal.PowRoundWide(a.hi, a.lo, n, fractionDigits, mode)
+	return Decimal{hi: hi, lo: lo}, err
+}
+
+// Sqrt returns square root rounded by mode, negative value is error.
+func (a Decimal) Sqrt(mode fpdecimal.RoundingMode) (Decimal, error) {
+	hi, lo, err := fpdecimal.SqrtRoundWide(a.hi, a.lo, fractionDigits, mode)
+	return Decimal{hi: hi, lo: lo}, err
+}
+
+// ApplyPercent returns percent of value rounded by mode, such as fee.
+// For basis points use fpdecimal.BasisPoints.Percent.
+func (a Decimal) ApplyPercent(p fpdecimal.Percent, mode fpdecimal.RoundingMode) (Decimal, error) {
+	hi, lo, err := p.ApplyWide(a.hi, a.lo, mode)
+	return Decimal{hi: hi, lo: lo}, err
+}
+
+// PercentOf returns how many percents is value of total, rounded by mode.
+func (a Decimal) PercentOf(total Decimal, mode fpdecimal.RoundingMode) (fpdecimal.Percent, error) {
+	return fpdecimal.PercentOfWide(a.hi, a.lo, total.hi, total.lo, mode)
+}
+
+// PercentChange returns change from one value to another relative to magnitude of first, rounded by mode.
+func PercentChange(from, to Decimal, mode fpdecimal.RoundingMode) (fpdecimal.Percent, error) {
+	return fpdecimal.PercentChangeWide(from.hi, from.lo, to.hi, to.lo, mode)
+}
+
+func (a Decimal) Equal(b Decimal) bool { return a == b }
+
+func (a Decimal) GreaterThan(b Decimal) bool { return a.Compare(b) > 0 }
+
+func (a Decimal) LessThan(b Decimal) bool { return a.Compare(b) < 0 }
+
+func (a Decimal) GreaterThanOrEqual(b Decimal) bool { return a.Compare(b) >= 0 }
+
+func (a Decimal) LessThanOrEqual(b Decimal) bool { return a.Compare(b) <= 0 }
+
+func (a Decimal) Compare(b Decimal) int {
+	switch {
+	case a.hi < b.hi:
+		return -1
+	case a.hi > b.hi:
+		return 1
+	case a.lo < b.lo:
+		return -1
+	case a.lo > b.lo:
+		return 1
+	}
+	return 0
+}
+
+func Min(vs ...Decimal) Decimal {
+	if len(vs) == 0 {
+		panic("min of empty set is undefined")
+	}
+	var v Decimal = vs[0]
+	for _, q := range vs {
+		if q.LessThan(v) {
+			v = q
+		}
+	}
+	return v
+}
+
+func Max(vs ...Decimal) Decimal {
+	if len(vs) == 0 {
+		panic("max of empty set is undefined")
+	}
+	var v Decimal = vs[0]
+	for _, q := range vs {
+		if q.GreaterThan(v) {
+			v = q
+		}
+	}
+	return v
+}
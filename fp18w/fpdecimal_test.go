@@ -0,0 +1,1112 @@
+package fp18w_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"math"
+	"math/big"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"unsafe"
+
+	"github.com/nikolaydubina/fpdecimal"
+	fp "github.com/nikolaydubina/fpdecimal/fp18w"
+)
+
+var (
+	multiplier = big.NewInt(1_000_000_000_000_000_000)
+	minValue   = new(big.Int).Neg(new(big.Int).Lsh(big.NewInt(1), 127))
+	maxValue   = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 127), big.NewInt(1))
+)
+
+func toBig(v fp.Decimal) *big.Int {
+	hi, lo := v.Scaled()
+	b := new(big.Int).Lsh(big.NewInt(hi), 64)
+	return b.Or(b, new(big.Int).SetUint64(lo))
+}
+
+// fromBig wraps around as int64 does.
+func fromBig(b *big.Int) fp.Decimal {
+	u := new(big.Int).And(b, new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1)))
+	lo := new(big.Int).And(u, new(big.Int).SetUint64(math.MaxUint64)).Uint64()
+	hi := new(big.Int).Rsh(u, 64).Uint64()
+	return fp.FromScaled(int64(hi), lo)
+}
+
+func FuzzArithmetics(f *testing.F) {
+	tests := [][4]uint64{
+		{0, 1, 0, 2},
+		{0, 1, math.MaxUint64, math.MaxUint64 - 4},
+		{0, 1100, 0, 0},
+		{0, 1_500_000_000_000_000_000, 0, 1_000_000_000_000_000_000},
+		{12345, 0, 0, 1_000_000_000_000_000_000},
+		{12345, 678, 9, 10},
+		{math.MaxInt64, math.MaxUint64, 1 << 63, 0},
+	}
+	for _, tc := range tests {
+		f.Add(tc[0], tc[1], tc[2], tc[3])
+	}
+	f.Fuzz(func(t *testing.T, ahi, alo, bhi, blo uint64) {
+		fa := fp.FromScaled(int64(ahi), alo)
+		fb := fp.FromScaled(int64(bhi), blo)
+		a, b := toBig(fa), toBig(fb)
+
+		if v := fa.Add(fb); v != fromBig(new(big.Int).Add(a, b)) {
+			t.Error("add", fa, fb, v)
+		}
+		if v := fa.Sub(fb); v != fromBig(new(big.Int).Sub(a, b)) {
+			t.Error("sub", fa, fb, v)
+		}
+		if v := fa.Mul(fb); v != fromBig(new(big.Int).Quo(new(big.Int).Mul(a, b), multiplier)) {
+			t.Error("mul", fa, fb, v)
+		}
+		if v := fa.Compare(fb); v != a.Cmp(b) {
+			t.Error("compare", fa, fb, v)
+		}
+		if (fa == fb) != fa.Equal(fb) || fa.LessThan(fb) != (a.Cmp(b) < 0) || fa.GreaterThanOrEqual(fb) != (a.Cmp(b) >= 0) {
+			t.Error("compare", fa, fb)
+		}
+
+		if b.Sign() != 0 {
+			// quotient has to fit
+			q := new(big.Int).Quo(new(big.Int).Mul(a, multiplier), b)
+			if q.Cmp(minValue) >= 0 && q.Cmp(maxValue) <= 0 {
+				if v := fa.Div(fb); v != fromBig(q) {
+					t.Error("div", fa, fb, v, q)
+				}
+			}
+		}
+
+		if m := new(big.Int).Quo(new(big.Int).Abs(b), multiplier); m.Sign() != 0 {
+			if v := fa.Mod(fb); v != fromBig(new(big.Int).Rem(a, m)) {
+				t.Error("mod", fa, fb, v)
+			}
+		}
+	})
+}
+
+func FuzzParse_StringRaw(f *testing.F) {
+	tests := []string{
+		"123.456",
+		"0.123",
+		"0.000000000000000001",
+		"0.0000000000000000001",
+		"0.123.2",
+		"0..1",
+		"123.1o2",
+		"--123",
+		"-",
+		"",
+		"170141183460469231731.687303715884105727",
+		"170141183460469231731.687303715884105728",
+	}
+	for _, tc := range tests {
+		f.Add(tc)
+		f.Add("-" + tc)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		v, err := fp.FromString(s)
+		if err != nil {
+			if v != fp.Zero {
+				t.Errorf("has to be 0 on error")
+			}
+			return
+		}
+
+		w, err := fp.FromString(v.String())
+		if err != nil || w != v {
+			t.Error(s, v, w, err)
+		}
+
+		// lossless up to 18 fractions, digits after that are not validated
+		if r, ok := new(big.Rat).SetString(s); ok && strings.Trim(s, "+-.0123456789") == "" {
+			q := new(big.Int).Quo(new(big.Int).Mul(r.Num(), multiplier), r.Denom())
+			if toBig(v).Cmp(q) != 0 {
+				t.Error(s, v, q)
+			}
+		}
+	})
+}
+
+func FuzzFloat(f *testing.F) {
+	tests := []float64{0, 0.5, 1, 123.456, 1e20}
+	for _, tc := range tests {
+		f.Add(tc)
+		f.Add(-tc)
+	}
+	f.Fuzz(func(t *testing.T, v float64) {
+		if math.Abs(v) >= 1.7e20 || math.IsNaN(v) {
+			t.Skip()
+		}
+
+		a := fp.FromFloat(v)
+		if delta := math.Abs(v - a.Float64()); delta > math.Abs(v)*1e-15 && delta > 1e-18 {
+			t.Error(v, a, a.Float64(), delta)
+		}
+	})
+}
+
+func TestFromInt(t *testing.T) {
+	tests := []struct {
+		v fp.Decimal
+		s string
+	}{
+		{fp.FromInt(0), "0"},
+		{fp.FromInt(-1), "-1"},
+		{fp.FromInt(int8(-128)), "-128"},
+		{fp.FromInt(uint64(math.MaxUint64)), "18446744073709551615"},
+		{fp.FromInt(int64(math.MinInt64)), "-9223372036854775808"},
+		{fp.FromIntScaled(-1), "-0.000000000000000001"},
+		{fp.FromIntScaled(uint64(math.MaxUint64)), "18.446744073709551615"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.s, func(t *testing.T) {
+			if s := tc.v.String(); s != tc.s {
+				t.Error(s, tc.s)
+			}
+		})
+	}
+}
+
+func TestFromFloat(t *testing.T) {
+	tests := []struct {
+		v float64
+		s string
+	}{
+		{0, "0"},
+		{-1.5, "-1.5"},
+		{1 << 50, "1125899906842624"},
+		{1 << 66, "73786976294838206464"},
+		{-(1 << 127) / 1e18, "-170141183460469231731.687303715884105728"},
+		{math.NaN(), "0"},
+		{math.Inf(1), "0"},
+		{-1.8e20, "0"},
+		{math.MaxFloat64, "0"},
+	}
+	for _, tc := range tests {
+		t.Run(fmt.Sprint(tc.v), func(t *testing.T) {
+			if v := fp.FromFloat(tc.v); v.String() != tc.s {
+				t.Error(v, tc.s)
+			}
+		})
+	}
+
+	if v := fp.FromFloat(float32(0.25)); v.String() != "0.25" {
+		t.Error(v)
+	}
+}
+
+func TestFromFloatRound(t *testing.T) {
+	// float of decimal is not exact, FromFloat can truncate it
+	for _, s := range []string{"0.029", "-0.029", "0.000000000000000001", "123456.789", "-170000000000000000000"} {
+		f, _ := strconv.ParseFloat(s, 64)
+		w, _ := fp.FromString(s)
+		if v, err := fp.FromFloatRound(f, fpdecimal.ToNearestEven); err != nil || v != w {
+			t.Error(s, v, w, err)
+		}
+	}
+
+	f32, _ := strconv.ParseFloat("0.029", 32)
+	if v, err := fp.FromFloatRound(float32(f32), fpdecimal.ToZero); err != nil || v.String() != "0.029" {
+		t.Error(v, err)
+	}
+
+	if n := testing.AllocsPerRun(100, func() { fp.FromFloatRound(float32(f32), fpdecimal.ToZero) }); n != 0 {
+		t.Error(n)
+	}
+
+	errs := []struct {
+		v   float64
+		err string
+	}{
+		{math.NaN(), "not finite"},
+		{math.Inf(1), "not finite"},
+		{math.Inf(-1), "not finite"},
+		{1.8e20, "overflow"},
+		{-1.8e20, "overflow"},
+		{math.MaxFloat64, "overflow"},
+	}
+	for _, tc := range errs {
+		t.Run(fmt.Sprint(tc.v), func(t *testing.T) {
+			if v, err := fp.FromFloatRound(tc.v, fpdecimal.ToNearestEven); err == nil || err.Error() != tc.err || v != fp.Zero {
+				t.Error(v, err)
+			}
+		})
+	}
+}
+
+func TestFromBigRat(t *testing.T) {
+	tests := []struct {
+		r    *big.Rat
+		mode fpdecimal.RoundingMode
+		s    string
+	}{
+		{big.NewRat(1, 3), fpdecimal.ToNearestEven, "0.333333333333333333"},
+		{big.NewRat(1, 3), fpdecimal.AwayFromZero, "0.333333333333333334"},
+		{big.NewRat(-2, 3), fpdecimal.ToNearestEven, "-0.666666666666666667"},
+		{big.NewRat(-2, 3), fpdecimal.ToZero, "-0.666666666666666666"},
+		{new(big.Rat).SetFrac(maxValue, multiplier), fpdecimal.ToNearestEven, "170141183460469231731.687303715884105727"},
+		{new(big.Rat).SetFrac(minValue, multiplier), fpdecimal.ToNearestEven, "-170141183460469231731.687303715884105728"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.r.String()+tc.mode.String(), func(t *testing.T) {
+			if v, err := fp.FromBigRat(tc.r, tc.mode); err != nil || v.String() != tc.s {
+				t.Error(v, tc.s, err)
+			}
+		})
+	}
+
+	overflow := new(big.Int).Add(maxValue, big.NewInt(1))
+	if v, err := fp.FromBigRat(new(big.Rat).SetInt(overflow), fpdecimal.ToNearestEven); err == nil || v != fp.Zero {
+		t.Error(v, err)
+	}
+	if v, err := fp.FromBigInt(overflow); err == nil || v != fp.Zero {
+		t.Error(v, err)
+	}
+	if v, err := fp.FromBigFloat(new(big.Float).SetInf(false), fpdecimal.ToNearestEven); err == nil || v != fp.Zero {
+		t.Error(v, err)
+	}
+}
+
+func FuzzBig(f *testing.F) {
+	tests := [][2]uint64{{0, 0}, {0, 1}, {0, 1100}, {12345, 678}, {math.MaxInt64, math.MaxUint64}, {1 << 63, 0}}
+	for _, tc := range tests {
+		f.Add(tc[0], tc[1])
+	}
+	f.Fuzz(func(t *testing.T, hi, lo uint64) {
+		v := fp.FromScaled(int64(hi), lo)
+
+		if b := v.BigInt(); b.Cmp(toBig(v)) != 0 {
+			t.Error(v, b)
+		}
+
+		if q, err := fp.FromBigRat(v.BigRat(), fpdecimal.ToZero); err != nil || q != v {
+			t.Error(v, q, err)
+		}
+
+		if q, err := fp.FromBigInt(v.BigInt()); err != nil || q != v {
+			t.Error(v, q, err)
+		}
+
+		if q, err := fp.FromString(v.BigRat().FloatString(18)); err != nil || q != v {
+			t.Error(v, q, err)
+		}
+
+		// float is not exact, but has more than 64 bits of mantissa
+		if q := v.BigFloat(); q.MinPrec() > 0 && q.Prec() < 64 {
+			t.Error(v, q)
+		}
+	})
+}
+
+func TestDivModByZero(t *testing.T) {
+	for _, f := range []func(){
+		func() { fp.FromInt(1).Div(fp.Zero) },
+		func() { fp.FromInt(1).Mod(fp.FromIntScaled(500_000_000_000_000_000)) },
+	} {
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Error("expected panic")
+				}
+			}()
+			f()
+		}()
+	}
+}
+
+func TestDecimalMemoryLayout(t *testing.T) {
+	a, _ := fp.FromString("-1000.123")
+	if v := unsafe.Sizeof(a); v != 16 {
+		t.Error(a, v)
+	}
+}
+
+func TestMinMax(t *testing.T) {
+	vs := []fp.Decimal{fp.FromInt(1), fp.FromIntScaled(-1), fp.FromScaled(1, 0)}
+	if v := fp.Min(vs...); v != fp.FromIntScaled(-1) {
+		t.Error(v)
+	}
+	if v := fp.Max(vs...); v != fp.FromScaled(1, 0) {
+		t.Error(v)
+	}
+}
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		s           string
+		whole, frac int64
+	}{
+		{"0", 0, 0},
+		{"12.345", 12, 345_000_000_000_000_000},
+		{"-12.345", -12, -345_000_000_000_000_000},
+		{"0.000000000000000001", 0, 1},
+		{"9223372036854775807.999999999999999999", math.MaxInt64, 999_999_999_999_999_999},
+		{"-9223372036854775808.999999999999999999", math.MinInt64, -999_999_999_999_999_999},
+	}
+	for _, tc := range tests {
+		t.Run(tc.s, func(t *testing.T) {
+			v, _ := fp.FromString(tc.s)
+			if whole, frac := v.Split(); whole != tc.whole || frac != tc.frac {
+				t.Error(whole, frac)
+			}
+			if v.IntPart() != tc.whole || v.FracPart() != fp.FromIntScaled(tc.frac) {
+				t.Error(v.IntPart(), v.FracPart())
+			}
+			if fp.FromInt(v.IntPart()).Add(v.FracPart()) != v {
+				t.Error(v)
+			}
+		})
+	}
+
+	// whole units beyond int64 wrap around
+	v, _ := fp.FromString("18446744073709551617.5")
+	if whole, frac := v.Split(); whole != 1 || frac != 500_000_000_000_000_000 {
+		t.Error(whole, frac)
+	}
+}
+
+func TestToInt(t *testing.T) {
+	half := fp.FromIntScaled(500_000_000_000_000_000)
+
+	// results for modes in order of fpdecimal.RoundingMode
+	tests := []struct {
+		v  fp.Decimal
+		vs [6]int64
+	}{
+		{fp.Zero, [6]int64{0, 0, 0, 0, 0, 0}},
+		{fp.FromInt(-5), [6]int64{-5, -5, -5, -5, -5, -5}},
+		{fp.FromInt(2).Add(half), [6]int64{2, 3, 2, 3, 2, 3}},
+		{fp.FromInt(-2).Sub(half), [6]int64{-2, -3, -2, -3, -3, -2}},
+		{fp.FromInt(3).Add(half), [6]int64{4, 4, 3, 4, 3, 4}},
+		{fp.FromIntScaled(1), [6]int64{0, 0, 0, 1, 0, 1}},
+		{fp.FromIntScaled(-1), [6]int64{0, 0, 0, -1, -1, 0}},
+		{fp.FromInt(int64(math.MinInt64)), [6]int64{math.MinInt64, math.MinInt64, math.MinInt64, math.MinInt64, math.MinInt64, math.MinInt64}},
+	}
+	for _, tc := range tests {
+		for mode := fpdecimal.ToNearestEven; mode <= fpdecimal.ToPositiveInf; mode++ {
+			t.Run(tc.v.String()+mode.String(), func(t *testing.T) {
+				if v, err := tc.v.ToInt(mode); err != nil || v != tc.vs[mode] {
+					t.Error(v, tc.vs[mode], err)
+				}
+			})
+		}
+	}
+
+	v := fp.FromInt(int64(math.MaxInt64)).Add(half)
+	if q, err := v.ToInt(fpdecimal.ToNearestAway); err == nil || q != 0 {
+		t.Error(q, err)
+	}
+	if q, err := v.ToInt(fpdecimal.ToZero); err != nil || q != math.MaxInt64 {
+		t.Error(q, err)
+	}
+}
+
+func TestIntArithmetics(t *testing.T) {
+	price, _ := fp.FromString("1.999999999999999999")
+
+	if v := price.MulInt(3); v.String() != "5.999999999999999997" {
+		t.Error(v)
+	}
+	if v := price.DivInt(2, fpdecimal.ToNearestEven); v.String() != "1" {
+		t.Error(v)
+	}
+	if v := price.DivInt(-2, fpdecimal.ToZero); v.String() != "-0.999999999999999999" {
+		t.Error(v)
+	}
+	if q, r := price.QuoRemInt(4); q.String() != "0.499999999999999999" || r.String() != "0.000000000000000003" || q.MulInt(4).Add(r) != price {
+		t.Error(q, r)
+	}
+	if q, r := price.MulInt(-1).QuoRemInt(4); q.String() != "-0.499999999999999999" || r.String() != "-0.000000000000000003" {
+		t.Error(q, r)
+	}
+
+	// scaling of multiplier by Mul overflows earlier
+	half := fromBig(new(big.Int).Rsh(maxValue, 1))
+	if v, err := half.MulIntChecked(2); err != nil || v != fromBig(new(big.Int).Sub(maxValue, big.NewInt(1))) {
+		t.Error(v, err)
+	}
+}
+
+func TestIntArithmetics_Error(t *testing.T) {
+	max, min := fromBig(maxValue), fromBig(minValue)
+
+	if v, err := max.MulIntChecked(2); err == nil || v != fp.Zero {
+		t.Error(v, err)
+	}
+	if v, err := min.MulIntChecked(-1); err == nil || v != fp.Zero {
+		t.Error(v, err)
+	}
+	if v, err := max.DivIntChecked(0, fpdecimal.ToZero); err == nil || v != fp.Zero {
+		t.Error(v, err)
+	}
+	if v, err := min.DivIntChecked(-1, fpdecimal.ToZero); err == nil || v != fp.Zero {
+		t.Error(v, err)
+	}
+	if q, r, err := max.QuoRemIntChecked(0); err == nil || q != fp.Zero || r != fp.Zero {
+		t.Error(q, r, err)
+	}
+	if q, r, err := min.QuoRemIntChecked(-1); err == nil || q != fp.Zero || r != fp.Zero {
+		t.Error(q, r, err)
+	}
+
+	// wraps around as integer division does
+	if v := min.DivInt(-1, fpdecimal.ToZero); v != min {
+		t.Error(v)
+	}
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic")
+		}
+	}()
+	max.DivInt(0, fpdecimal.ToZero)
+}
+
+func FuzzIntArithmetics(f *testing.F) {
+	tests := []struct {
+		hi int64
+		lo uint64
+		n  int64
+	}{
+		{0, 0, 1},
+		{0, 1, 3},
+		{0, 1999, 3},
+		{-1, math.MaxUint64 - 1998, -3},
+		{math.MaxInt64, math.MaxUint64, 2},
+		{math.MinInt64, 0, -1},
+		{12345, 678, math.MinInt64},
+	}
+	for _, tc := range tests {
+		f.Add(tc.hi, tc.lo, tc.n)
+	}
+	f.Fuzz(func(t *testing.T, hi int64, lo uint64, n int64) {
+		x := fp.FromScaled(hi, lo)
+		a := toBig(x)
+		fits := func(v *big.Int) bool { return v.Cmp(minValue) >= 0 && v.Cmp(maxValue) <= 0 }
+
+		p := new(big.Int).Mul(a, big.NewInt(n))
+		if v := x.MulInt(n); v != fromBig(p) {
+			t.Error(x, n, v)
+		}
+		if v, err := x.MulIntChecked(n); fits(p) != (err == nil) || (err == nil && v != fromBig(p)) {
+			t.Error(x, n, v, err)
+		}
+
+		if n == 0 {
+			if _, err := x.DivIntChecked(n, fpdecimal.ToZero); err == nil {
+				t.Error(x)
+			}
+			if _, _, err := x.QuoRemIntChecked(n); err == nil {
+				t.Error(x)
+			}
+			return
+		}
+
+		q, m := new(big.Int).QuoRem(a, big.NewInt(n), new(big.Int))
+		if qu, ru := x.QuoRemInt(n); qu != fromBig(q) || ru != fromBig(m) {
+			t.Error(x, n, qu, ru)
+		}
+		if !fits(q) {
+			if _, _, err := x.QuoRemIntChecked(n); err == nil {
+				t.Error(x, n)
+			}
+			return
+		}
+
+		qv, rv, err := x.QuoRemIntChecked(n)
+		if err != nil || qv != fromBig(q) || rv != fromBig(m) {
+			t.Error(x, n, qv, rv, err)
+		}
+
+		for mode := fpdecimal.ToNearestEven; mode <= fpdecimal.ToPositiveInf; mode++ {
+			v, err := x.DivIntChecked(n, mode)
+			if err != nil || v != x.DivInt(n, mode) {
+				t.Error(x, n, mode, v, err)
+			}
+			// rounded result differs from truncated by at most one minor unit
+			if d := new(big.Int).Sub(toBig(v), q); d.CmpAbs(big.NewInt(1)) > 0 {
+				t.Error(x, n, mode, v, q)
+			}
+		}
+	})
+}
+
+func TestAllocate(t *testing.T) {
+	total := fp.FromInt(1)
+
+	parts := total.SplitN(3)
+	if len(parts) != 3 || parts[0] != parts[1].Add(fp.FromIntScaled(1)) || parts[1] != parts[2] {
+		t.Error(parts)
+	}
+	if v := fp.Zero.Add(parts[0]).Add(parts[1]).Add(parts[2]); v != total {
+		t.Error(v)
+	}
+
+	fee, _ := fp.FromString("1.000000000000000001")
+	if parts := fee.Allocate(1, 0, 3); parts[0].String() != "0.25" || parts[1] != fp.Zero || parts[2].String() != "0.750000000000000001" {
+		t.Error(parts)
+	}
+	if parts := fee.MulInt(-1).Allocate(1, 0, 3); parts[0].String() != "-0.25" || parts[1] != fp.Zero || parts[2].String() != "-0.750000000000000001" {
+		t.Error(parts)
+	}
+
+	// fraction of unit goes to first part of largest ratio
+	cent, _ := fp.FromString("0.01")
+	if parts := fee.AllocateUnit(cent, 1, 1, 2); parts[0].String() != "0.25" || parts[1].String() != "0.25" || parts[2].String() != "0.500000000000000001" {
+		t.Error(parts)
+	}
+
+	// units of large value do not fit 64 bits
+	max := fromBig(maxValue)
+	if parts := max.AllocateUnit(fp.FromInt(1), math.MaxInt64, math.MaxInt64); parts[0].Add(parts[1]) != max || parts[1].String() != "85070591730234615865" {
+		t.Error(parts)
+	}
+
+	for _, f := range []func(){
+		func() { fee.AllocateUnit(fp.Zero, 1) },
+		func() { fee.Allocate(1, -1) },
+		func() { fee.Allocate(0, 0) },
+		func() { fee.Allocate(math.MaxInt64, math.MaxInt64, 2) },
+		func() { fee.SplitN(0) },
+	} {
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Error("expected panic")
+				}
+			}()
+			f()
+		}()
+	}
+}
+
+func FuzzAllocate(f *testing.F) {
+	tests := [][2]uint64{{0, 0}, {0, 1}, {0, 1001}, {12345, 678}, {math.MaxInt64, math.MaxUint64}, {1 << 63, 0}}
+	for _, tc := range tests {
+		f.Add(tc[0], tc[1], 3)
+		f.Add(tc[0], tc[1], 7)
+	}
+	f.Fuzz(func(t *testing.T, hi, lo uint64, n int) {
+		if n <= 0 || n > 100 {
+			t.Skip()
+		}
+		v := fp.FromScaled(int64(hi), lo)
+
+		parts := v.SplitN(n)
+		sum := fp.Zero
+		for i, p := range parts {
+			sum = sum.Add(p)
+			if d := toBig(parts[0].Sub(p)); d.CmpAbs(big.NewInt(1)) > 0 || (i > 0 && parts[i-1].Compare(p)*v.Compare(fp.Zero) < 0) {
+				t.Error(v, n, parts)
+			}
+		}
+		if len(parts) != n || sum != v {
+			t.Error(v, n, parts)
+		}
+	})
+}
+
+func TestPercent(t *testing.T) {
+	price, _ := fp.FromString("1.999999999999999999")
+	fee, _ := fpdecimal.PercentFromString("2.5%")
+	max := fromBig(maxValue)
+
+	if v, err := price.ApplyPercent(fee, fpdecimal.ToNearestEven); err != nil || v.String() != "0.05" {
+		t.Error(v, err)
+	}
+	if v, err := price.ApplyPercent(fpdecimal.BasisPointsFromInt(250).Percent(), fpdecimal.ToZero); err != nil || v.String() != "0.049999999999999999" {
+		t.Error(v, err)
+	}
+	if v, err := max.ApplyPercent(fpdecimal.PercentFromInt(100), fpdecimal.ToZero); err != nil || v != max {
+		t.Error(v, err)
+	}
+	if v, err := max.ApplyPercent(fpdecimal.PercentFromInt(101), fpdecimal.ToZero); err == nil || v != fp.Zero {
+		t.Error(v, err)
+	}
+
+	if p, err := fp.FromIntScaled(1).PercentOf(fp.FromIntScaled(8), fpdecimal.ToNearestEven); err != nil || p.String() != "12.5%" {
+		t.Error(p, err)
+	}
+	if p, err := max.PercentOf(max, fpdecimal.ToNearestEven); err != nil || p.String() != "100%" {
+		t.Error(p, err)
+	}
+	if p, err := price.PercentOf(fp.Zero, fpdecimal.ToNearestEven); err == nil || p.Scaled() != 0 {
+		t.Error(p, err)
+	}
+
+	if p, err := fp.PercentChange(fp.FromIntScaled(8), fp.FromIntScaled(10), fpdecimal.ToNearestEven); err != nil || p.String() != "25%" {
+		t.Error(p, err)
+	}
+	if p, err := fp.PercentChange(max, max.MulInt(-1), fpdecimal.ToNearestEven); err != nil || p.String() != "-200%" {
+		t.Error(p, err)
+	}
+}
+
+func TestPow(t *testing.T) {
+	two := fp.FromInt(2)
+
+	if v, err := two.Pow(0, fpdecimal.ToNearestEven); err != nil || v != fp.FromInt(1) {
+		t.Error(v, err)
+	}
+	if v, err := two.Pow(3, fpdecimal.ToNearestEven); err != nil || v != fp.FromInt(8) {
+		t.Error(v, err)
+	}
+	if v, err := two.Pow(-2, fpdecimal.ToNearestEven); err != nil || v.String() != "0.25" {
+		t.Error(v, err)
+	}
+	if v, err := two.Pow(67, fpdecimal.ToNearestEven); err != nil || v.String() != "147573952589676412928" {
+		t.Error(v, err)
+	}
+	if v, err := two.MulInt(-1).Pow(68, fpdecimal.ToNearestEven); err == nil || v != fp.Zero {
+		t.Error(v, err)
+	}
+	if v, err := fp.Zero.Pow(-1, fpdecimal.ToNearestEven); err == nil || v != fp.Zero {
+		t.Error(v, err)
+	}
+}
+
+func TestSqrt(t *testing.T) {
+	if v, err := fp.FromInt(4).Sqrt(fpdecimal.ToNearestEven); err != nil || v != fp.FromInt(2) {
+		t.Error(v, err)
+	}
+	if v, err := fp.FromInt(2).Sqrt(fpdecimal.ToNearestEven); err != nil || v.String() != "1.414213562373095049" {
+		t.Error(v, err)
+	}
+	if v, err := fp.FromIntScaled(-1).Sqrt(fpdecimal.ToNearestEven); err == nil || v != fp.Zero {
+		t.Error(v, err)
+	}
+}
+
+func FuzzSqrt(f *testing.F) {
+	tests := [][2]uint64{{0, 0}, {0, 1}, {0, 2}, {0, 1100}, {12345, 678}, {math.MaxInt64, math.MaxUint64}}
+	for _, tc := range tests {
+		f.Add(tc[0], tc[1])
+	}
+	f.Fuzz(func(t *testing.T, hi, lo uint64) {
+		v := fp.FromScaled(int64(hi), lo)
+		if v.LessThan(fp.Zero) {
+			t.Skip()
+		}
+
+		lo1, err := v.Sqrt(fpdecimal.ToZero)
+		if err != nil {
+			t.Fatal(err)
+		}
+		hi1, err := v.Sqrt(fpdecimal.ToPositiveInf)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// square of root truncated is not above value, and of next is above
+		x := lo1.BigRat()
+		if x.Mul(x, x).Cmp(v.BigRat()) > 0 {
+			t.Error(v, lo1)
+		}
+		next := lo1.Add(fp.FromIntScaled(1)).BigRat()
+		if next.Mul(next, next).Cmp(v.BigRat()) <= 0 {
+			t.Error(v, lo1)
+		}
+		if d := hi1.Sub(lo1); d != fp.Zero && d != fp.FromIntScaled(1) {
+			t.Error(v, lo1, hi1)
+		}
+	})
+}
+
+func TestJSON(t *testing.T) {
+	type Position struct {
+		Notional fp.Decimal `json:"notional"`
+	}
+
+	var v Position
+	if err := json.Unmarshal([]byte(`{"notional": 12345678901234567890.123456789012345678}`), &v); err != nil {
+		t.Error(err)
+	}
+	if s := v.Notional.String(); s != "12345678901234567890.123456789012345678" {
+		t.Error(s)
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil || string(b) != `{"notional":12345678901234567890.123456789012345678}` {
+		t.Error(string(b), err)
+	}
+
+	if b := fp.AppendArray(nil, []fp.Decimal{fp.FromInt(1), fp.FromIntScaled(-500_000_000_000_000_000)}); string(b) != "[1,-0.5]" {
+		t.Error(string(b))
+	}
+
+	if err := json.Unmarshal([]byte(`{"notional": 170141183460469231731.687303715884105728}`), &v); err == nil {
+		t.Error("expected error")
+	}
+}
+
+var scaledForTests = [][2]uint64{
+	{0, 0},
+	{0, 1},
+	{0, 1_100_000_000_000_000_000},
+	{1, 0},
+	{0x21e, 0x19e0c9bab2400000},
+	{math.MaxInt64, math.MaxUint64},
+	{1 << 63, 0},
+}
+
+func FuzzCBOR(f *testing.F) {
+	for _, tc := range scaledForTests {
+		f.Add(int64(tc[0]), tc[1])
+	}
+	f.Fuzz(func(t *testing.T, hi int64, lo uint64) {
+		v := fp.FromScaled(hi, lo)
+		b, err := v.MarshalCBOR()
+		if err != nil {
+			t.Error(err)
+		}
+		var q fp.Decimal
+		if err := q.UnmarshalCBOR(b); err != nil || q != v {
+			t.Error(v, q, err)
+		}
+	})
+}
+
+func FuzzMsgpack(f *testing.F) {
+	for _, tc := range scaledForTests {
+		f.Add(int64(tc[0]), tc[1])
+	}
+	f.Fuzz(func(t *testing.T, hi int64, lo uint64) {
+		v := fp.FromScaled(hi, lo)
+		b, err := v.MarshalMsgpack()
+		if err != nil {
+			t.Error(err)
+		}
+		var q fp.Decimal
+		if err := q.UnmarshalMsgpack(b); err != nil || q != v {
+			t.Error(v, q, err)
+		}
+	})
+}
+
+func FuzzDecimal128(f *testing.F) {
+	for _, tc := range scaledForTests {
+		f.Add(int64(tc[0]), tc[1])
+	}
+	f.Fuzz(func(t *testing.T, hi int64, lo uint64) {
+		v := fp.FromScaled(hi, lo)
+		dhi, dlo, err := v.Decimal128()
+		if err != nil {
+			// decimal128 has 34 digits
+			if s := strings.TrimRight(strings.Trim(toBig(v).String(), "-"), "0"); len(s) <= 34 {
+				t.Error(v, err)
+			}
+			return
+		}
+		if q, err := fp.FromDecimal128(dhi, dlo); err != nil || q != v {
+			t.Error(v, q, err)
+		}
+	})
+}
+
+func TestDecimal128(t *testing.T) {
+	v, _ := fp.FromString("100000000000000000000")
+	hi, lo, err := v.Decimal128()
+	if err != nil || hi != 0x3026314dc6448d93 || lo != 0x38c15b0a00000000 {
+		t.Errorf("%#x %#x %v", hi, lo, err)
+	}
+
+	if hi, lo, err := fp.FromScaled(math.MaxInt64, math.MaxUint64).Decimal128(); err == nil || hi != 0 || lo != 0 {
+		t.Error(hi, lo, err)
+	}
+}
+
+func FuzzUnscaled(f *testing.F) {
+	for _, tc := range scaledForTests {
+		f.Add(int64(tc[0]), tc[1], uint8(38), uint8(18))
+		f.Add(int64(tc[0]), tc[1], uint8(38), uint8(0))
+	}
+	f.Fuzz(func(t *testing.T, hi int64, lo uint64, precision, scale uint8) {
+		v := fp.FromScaled(hi, lo)
+
+		uhi, ulo, err := v.Unscaled(precision, scale)
+		if err != nil {
+			return
+		}
+		if q, err := fp.FromUnscaled(uhi, ulo, precision, scale); err != nil || q != v {
+			t.Error(v, q, err)
+		}
+
+		b, err := v.AppendUnscaledBytes(nil, 16, precision, scale)
+		if err != nil {
+			t.Error(err)
+		}
+		if q, err := fp.FromUnscaledBytes(b, precision, scale); err != nil || q != v {
+			t.Error(v, q, err)
+		}
+	})
+}
+
+func FuzzPostgresNumeric(f *testing.F) {
+	for _, tc := range scaledForTests {
+		f.Add(int64(tc[0]), tc[1])
+	}
+	f.Fuzz(func(t *testing.T, hi int64, lo uint64) {
+		v := fp.FromScaled(hi, lo)
+		q, err := fp.FromPostgresNumeric(v.AppendPostgresNumeric(nil))
+		if err != nil || q != v {
+			t.Error(v, q, err)
+		}
+	})
+}
+
+func FuzzArray(f *testing.F) {
+	for _, tc := range scaledForTests {
+		f.Add(int64(tc[0]), tc[1])
+	}
+	f.Fuzz(func(t *testing.T, hi int64, lo uint64) {
+		vs := []fp.Decimal{fp.FromScaled(hi, lo), fp.FromIntScaled(-1)}
+
+		s := fp.AppendArray(nil, vs)
+
+		var q []fp.Decimal
+		for v, err := range fp.DecodeArray(strings.NewReader(string(s))) {
+			if err != nil {
+				t.Error(err)
+			}
+			q = append(q, v)
+		}
+		if !slices.Equal(q, vs) {
+			t.Error(string(s), q)
+		}
+	})
+}
+
+func TestText(t *testing.T) {
+	type Trade struct {
+		Notional fp.Decimal `xml:"Notional,attr"`
+		Price    fp.Decimal `xml:",chardata"`
+	}
+
+	var v Trade
+	if err := xml.Unmarshal([]byte(`<Trade Notional=" 1_000_000_000_000_000_000 "> "0.5" </Trade>`), &v); err != nil {
+		t.Fatal(err)
+	}
+	if v.Notional != fp.FromInt(uint64(1_000_000_000_000_000_000)) || v.Price != fp.FromIntScaled(500_000_000_000_000_000) {
+		t.Error(v)
+	}
+
+	b, err := xml.Marshal(v)
+	if err != nil || string(b) != `<Trade Notional="1000000000000000000">0.5</Trade>` {
+		t.Error(string(b), err)
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	t.Setenv("TEST_LIMIT", "100_000_000_000_000_000_000")
+	t.Setenv("TEST_BAD", "1,5")
+
+	if v, err := fp.FromEnv("TEST_LIMIT", fp.Zero); err != nil || v.String() != "100000000000000000000" {
+		t.Error(v, err)
+	}
+	if v, err := fp.FromEnv("TEST_NOT_SET", fp.FromInt(5)); err != nil || v != fp.FromInt(5) {
+		t.Error(v, err)
+	}
+	if v, err := fp.FromEnv("TEST_BAD", fp.FromInt(5)); err == nil || err.Error() != "env TEST_BAD: bad digit" || v != fp.Zero {
+		t.Error(v, err)
+	}
+}
+
+func TestFlagSet(t *testing.T) {
+	var v fp.Decimal
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.Var(&v, "limit", "limit")
+
+	if err := fs.Parse([]string{"-limit", "1.5"}); err != nil || v != fp.FromIntScaled(1_500_000_000_000_000_000) {
+		t.Error(v, err)
+	}
+	if err := fs.Parse([]string{"-limit", "1.5x"}); err == nil {
+		t.Error("expected error")
+	}
+	if v.Type() != "decimal" {
+		t.Error(v.Type())
+	}
+
+	limit := fp.Flag("test-limit", fp.FromInt(1), "limit")
+	if *limit != fp.FromInt(1) {
+		t.Error(*limit)
+	}
+}
+
+func TestLogValue(t *testing.T) {
+	var b bytes.Buffer
+	log := slog.New(slog.NewJSONHandler(&b, &slog.HandlerOptions{ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+		if a.Key == slog.TimeKey && len(groups) == 0 {
+			return slog.Attr{}
+		}
+		return a
+	}}))
+	log.Info("position", fp.Attr("notional", fp.FromScaled(1, 0)), "fee", fp.FromIntScaled(-500_000_000_000_000_000))
+	if s := b.String(); s != `{"level":"INFO","msg":"position","notional":18.446744073709551616,"fee":-0.5}`+"\n" {
+		t.Error(s)
+	}
+}
+
+var floatsForTests = []struct {
+	name string
+	vals []string
+}{
+	{
+		name: "small",
+		vals: []string{
+			"123.456",
+			"0.123",
+			"0.012",
+			"0.001",
+			"0.982",
+			"0.101",
+			"10",
+			"11",
+			"1",
+		},
+	},
+	{
+		name: "large",
+		vals: []string{
+			"123123123112312123123.123456789012345678",
+			"5341320482340234.123",
+		},
+	},
+}
+
+func BenchmarkParse(b *testing.B) {
+	var s fp.Decimal
+	var err error
+
+	b.Run("fromString", func(b *testing.B) {
+		for _, tc := range floatsForTests {
+			b.ResetTimer()
+			b.Run(tc.name, func(b *testing.B) {
+				for n := 0; n < b.N; n++ {
+					s, err = fp.FromString(tc.vals[n%len(tc.vals)])
+					if err != nil || s == fp.Zero {
+						b.Error(s, err)
+					}
+				}
+			})
+		}
+	})
+
+	b.Run("UnmarshalJSON", func(b *testing.B) {
+		for _, tc := range floatsForTests {
+			var vals [][]byte
+			for i := range tc.vals {
+				vals = append(vals, []byte(tc.vals[i]))
+			}
+
+			b.ResetTimer()
+			b.Run(tc.name, func(b *testing.B) {
+				for n := 0; n < b.N; n++ {
+					if err = s.UnmarshalJSON(vals[n%len(vals)]); err != nil || s == fp.Zero {
+						b.Error(s, err)
+					}
+				}
+			})
+		}
+	})
+}
+
+func BenchmarkPrint(b *testing.B) {
+	var s string
+	for _, tc := range floatsForTests {
+		tests := make([]fp.Decimal, 0, len(tc.vals))
+		for _, q := range tc.vals {
+			v, err := fp.FromString(q)
+			if err != nil {
+				b.Error(err)
+			}
+			tests = append(tests, v)
+			tests = append(tests, fp.Zero.Sub(v))
+		}
+
+		b.Run("String", func(b *testing.B) {
+			b.ResetTimer()
+			b.Run(tc.name, func(b *testing.B) {
+				for n := 0; n < b.N; n++ {
+					s = tests[n%len(tests)].String()
+					if s == "" {
+						b.Error("empty str")
+					}
+				}
+			})
+		})
+	}
+}
+
+func BenchmarkArithmetic(b *testing.B) {
+	x, _ := fp.FromString("251.231")
+	y, _ := fp.FromString("21231.001")
+	z, _ := fp.FromString("123123123112312123123.123456789012345678")
+
+	var s, u fp.Decimal
+
+	b.Run("add", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			s = x.Add(y)
+		}
+	})
+
+	b.Run("mul", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			s = x.Mul(y)
+		}
+	})
+
+	b.Run("div", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			s = x.Div(y)
+		}
+	})
+
+	b.Run("div/large", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			s = z.Div(y)
+		}
+	})
+
+	b.Run("divmod", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			s, u = x.DivMod(y)
+		}
+	})
+
+	if s == fp.Zero || u == fp.Zero {
+		b.Error()
+	}
+}
+
+func ExampleDecimal() {
+	// notional values in IDR do not fit int64 with 18 fractions
+	positions := `[
+		{"notional": 95000000000000.5},
+		{"notional": 120000000000000.25}
+	]`
+
+	var vs []struct {
+		Notional fp.Decimal `json:"notional"`
+	}
+	if err := json.NewDecoder(strings.NewReader(positions)).Decode(&vs); err != nil {
+		log.Fatal(err)
+	}
+
+	total := fp.Zero
+	for _, v := range vs {
+		total = total.Add(v.Notional)
+	}
+
+	fmt.Println(total, total.Mul(fp.FromIntScaled(1_000_000_000_000_000)))
+	// Output: 215000000000000.75 215000000000.00075
+}
@@ -0,0 +1,131 @@
+package fp18w
+
+import "math/bits"
+
+// uint128 is magnitude of Decimal.
+type uint128 struct{ hi, lo uint64 }
+
+func (a Decimal) abs() (uint128, bool) {
+	if a.hi >= 0 {
+		return uint128{uint64(a.hi), a.lo}, false
+	}
+	lo, c := bits.Sub64(0, a.lo, 0)
+	hi, _ := bits.Sub64(0, uint64(a.hi), c)
+	return uint128{hi, lo}, true
+}
+
+// fromAbs returns value of magnitude, wrapping around as int64 does.
+func fromAbs(u uint128, neg bool) Decimal {
+	if neg {
+		var c uint64
+		u.lo, c = bits.Sub64(0, u.lo, 0)
+		u.hi, _ = bits.Sub64(0, u.hi, c)
+	}
+	return Decimal{hi: int64(u.hi), lo: u.lo}
+}
+
+// mul64 returns 192 bits of u*m, most significant word first.
+func mul64(u uint128, m uint64) [3]uint64 {
+	h, l := bits.Mul64(u.lo, m)
+	hh, hl := bits.Mul64(u.hi, m)
+	hl, c := bits.Add64(hl, h, 0)
+	return [3]uint64{hh + c, hl, l}
+}
+
+// mul128 returns 256 bits of a*b, most significant word first.
+func mul128(a, b uint128) [4]uint64 {
+	var r [4]uint64
+	var c uint64
+
+	h, l := bits.Mul64(a.lo, b.lo)
+	r[3] = l
+	r[2] = h
+
+	h, l = bits.Mul64(a.hi, b.lo)
+	r[2], c = bits.Add64(r[2], l, 0)
+	r[1], c = bits.Add64(h, 0, c)
+
+	h, l = bits.Mul64(a.lo, b.hi)
+	r[2], c = bits.Add64(r[2], l, 0)
+	r[1], c = bits.Add64(r[1], h, c)
+	r[0] = c
+
+	h, l = bits.Mul64(a.hi, b.hi)
+	r[1], c = bits.Add64(r[1], l, 0)
+	r[0] += h + c
+
+	return r
+}
+
+// div64 divides number of words, most significant first, by d in place.
+// Returns remainder. Panics when d is zero.
+func div64(u []uint64, d uint64) uint64 {
+	var r uint64
+	for i := range u {
+		u[i], r = bits.Div64(r, u[i], d)
+	}
+	return r
+}
+
+// divmod192 returns quotient and remainder of u by d, quotient has to fit 128 bits.
+// Panics when d is zero.
+func divmod192(u [3]uint64, d uint128) (q, r uint128) {
+	if d.hi == 0 {
+		r.lo = div64(u[:], d.lo)
+		return uint128{u[1], u[2]}, r
+	}
+
+	// Knuth, The Art of Computer Programming, Vol. 2, 4.3.1, Algorithm D
+	s := uint(bits.LeadingZeros64(d.hi))
+	dh, dl := d.hi<<s|d.lo>>(64-s), d.lo<<s
+	u3, u2, u1, u0 := u[0]>>(64-s), u[0]<<s|u[1]>>(64-s), u[1]<<s|u[2]>>(64-s), u[2]<<s
+
+	var r1, r0 uint64
+	q.hi, r1, r0 = divStep(u3, u2, u1, dh, dl)
+	q.lo, r1, r0 = divStep(r1, r0, u0, dh, dl)
+
+	return q, uint128{r1 >> s, r0>>s | r1<<(64-s)}
+}
+
+// divStep divides n2:n1:n0 by normalized dh:dl, when n2:n1 is less than dh:dl.
+// Returns quotient digit and remainder.
+func divStep(n2, n1, n0, dh, dl uint64) (q, r1, r0 uint64) {
+	var rhat, c uint64
+	if n2 >= dh {
+		q = 1<<64 - 1
+		rhat, c = bits.Add64(n1, dh, 0)
+	} else {
+		q, rhat = bits.Div64(n2, n1, dh)
+	}
+
+	for c == 0 {
+		ph, pl := bits.Mul64(q, dl)
+		if ph < rhat || (ph == rhat && pl <= n0) {
+			break
+		}
+		q--
+		rhat, c = bits.Add64(rhat, dh, 0)
+	}
+
+	ph, pl := bits.Mul64(q, dl)
+	qh, ql := bits.Mul64(q, dh)
+	p1, c := bits.Add64(ql, ph, 0)
+	p2 := qh + c
+
+	var b uint64
+	r0, b = bits.Sub64(n0, pl, 0)
+	r1, b = bits.Sub64(n1, p1, b)
+	_, b = bits.Sub64(n2, p2, b)
+	if b != 0 {
+		q--
+		r0, c = bits.Add64(r0, dl, 0)
+		r1, _ = bits.Add64(r1, dh, c)
+	}
+	return q, r1, r0
+}
+
+// mul returns lower 128 bits of u*m.
+func (u uint128) mul(m uint64) uint128 {
+	p := mul64(u, m)
+	return uint128{p[1], p[2]}
+}
@@ -0,0 +1,4 @@
+// Package fp3 is fixed-point decimal of 3 fractional digits.
+// Conversion to and from fp6 and multiplication by fp6 price are in this package, as FromFP6, Decimal.FP6 and Decimal.MulFP6.
+// There is no fp6.FromFP3, since fp6 importing fp3 back would be import cycle.
+package fp3
@@ -0,0 +1,219 @@
+package fp3_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	fp "github.com/nikolaydubina/fpdecimal/fp3"
+)
+
+func ExampleDecimal() {
+	var BuySP500Price = fp.FromInt(9000)
+
+	input := []byte(`{"sp500": 9000.023}`)
+
+	type Stocks struct {
+		SP500 fp.Decimal `json:"sp500"`
+	}
+	var v Stocks
+	if err := json.Unmarshal(input, &v); err != nil {
+		log.Fatal(err)
+	}
+
+	var amountToBuy fp.Decimal
+	if v.SP500.GreaterThan(BuySP500Price) {
+		amountToBuy = amountToBuy.Add(v.SP500.Mul(fp.FromInt(2)))
+	}
+
+	fmt.Println(amountToBuy)
+	// Output: 18000.046
+}
+
+func ExampleDecimal_skip_whole_fraction() {
+	v, _ := fp.FromString("1013.0000")
+	fmt.Println(v)
+	// Output: 1013
+}
+
+func ExampleDecimal_skip_trailing_zeros() {
+	v, _ := fp.FromString("102.0020")
+	fmt.Println(v)
+	// Output: 102.002
+}
+
+func ExampleDecimal_Div() {
+	x, _ := fp.FromString("1.000")
+	p := x.Div(fp.FromInt(3))
+	fmt.Print(p)
+	// Output: 0.333
+}
+
+func ExampleDecimal_Div_whole() {
+	x, _ := fp.FromString("1.000")
+	p := x.Div(fp.FromInt(5))
+	fmt.Print(p)
+	// Output: 0.2
+}
+
+func ExampleDecimal_Mod() {
+	x, _ := fp.FromString("1.000")
+	m := x.Mod(fp.FromInt(3))
+	fmt.Print(m)
+	// Output: 0.001
+}
+
+func ExampleDecimal_DivMod() {
+	x, _ := fp.FromString("1.000")
+	p, m := x.DivMod(fp.FromInt(3))
+	fmt.Print(p, m)
+	// Output: 0.333 0.001
+}
+
+func ExampleDecimal_DivMod_whole() {
+	x, _ := fp.FromString("1.000")
+	p, m := x.DivMod(fp.FromInt(5))
+	fmt.Print(p, m)
+	// Output: 0.2 0
+}
+
+func ExampleFromInt_uint8() {
+	var x uint8 = 100
+	v := fp.FromInt(x)
+	fmt.Print(v)
+	// Output: 100
+}
+
+func ExampleFromInt_int8() {
+	var x int8 = -100
+	v := fp.FromInt(x)
+	fmt.Print(v)
+	// Output: -100
+}
+
+func ExampleFromInt_int() {
+	var x int = -100
+	v := fp.FromInt(x)
+	fmt.Print(v)
+	// Output: -100
+}
+
+func ExampleFromInt_uint() {
+	var x uint = 100
+	v := fp.FromInt(x)
+	fmt.Print(v)
+	// Output: 100
+}
+
+func ExampleMin() {
+	min := fp.Min(fp.FromInt(100), fp.FromFloat(0.999), fp.FromFloat(100.001))
+	fmt.Print(min)
+	// Output: 0.999
+}
+
+func ExampleMin_empty() {
+	defer func() { fmt.Print(recover()) }()
+	fp.Min()
+	// Output: min of empty set is undefined
+}
+
+func ExampleMax() {
+	max := fp.Max(fp.FromInt(100), fp.FromFloat(0.999), fp.FromFloat(100.001))
+	fmt.Print(max)
+	// Output: 100.001
+}
+
+func ExampleMax_empty() {
+	defer func() { fmt.Print(recover()) }()
+	fp.Max()
+	// Output: max of empty set is undefined
+}
+
+func ExampleDecimal_UnmarshalCBOR() {
+	// 273.15 as in RFC 8949
+	var v fp.Decimal
+	err := v.UnmarshalCBOR([]byte{0xc4, 0x82, 0x21, 0x19, 0x6a, 0xb3})
+	fmt.Println(v, err)
+	// Output: 273.15 <nil>
+}
+
+func ExampleFromDecimal128() {
+	// BSON Decimal128 of "-1.5"
+	v, err := fp.FromDecimal128(0xb03e000000000000, 15)
+	fmt.Println(v, err)
+
+	// BSON Decimal128 of "0.0001"
+	_, err = fp.FromDecimal128(0x3038000000000000, 1)
+	fmt.Println(err)
+	// Output:
+	// -1.5 <nil>
+	// more fraction digits than supported
+}
+
+func ExampleDecimal_Unscaled() {
+	v, _ := fp.FromString("12.5")
+
+	u, err := v.Unscaled(18, 2)
+	fmt.Println(u, err)
+
+	b, err := v.AppendUnscaledBytes(nil, 4, 9, 2)
+	fmt.Println(b, err)
+
+	_, err = v.Unscaled(18, 0)
+	fmt.Println(err)
+	// Output:
+	// 1250 <nil>
+	// [0 0 4 226] <nil>
+	// more fraction digits than supported
+}
+
+func ExampleFromUnscaled() {
+	// DECIMAL(18,5) column
+	v, err := fp.FromUnscaled(1234500, 18, 5)
+	fmt.Println(v, err)
+	// Output: 12.345 <nil>
+}
+
+func ExampleFromPostgresNumeric() {
+	// NaN
+	_, err := fp.FromPostgresNumeric([]byte{0, 0, 0, 0, 0xc0, 0, 0, 0})
+	fmt.Println(err)
+
+	// 12345.678
+	v, err := fp.FromPostgresNumeric([]byte{0, 3, 0, 1, 0, 0, 0, 3, 0, 1, 0x09, 0x29, 0x1a, 0x7c})
+	fmt.Println(v, err)
+	// Output:
+	// postgres numeric: NaN
+	// 12345.678 <nil>
+}
+
+func ExampleDecodeArray() {
+	prices := strings.NewReader(`[1.23, 4.56, "7.89", -0.001]`)
+
+	total := fp.Zero
+	for v, err := range fp.DecodeArray(prices) {
+		if err != nil {
+			log.Fatal(err)
+		}
+		total = total.Add(v)
+	}
+
+	fmt.Println(total)
+	// Output: 13.679
+}
+
+func ExampleAppendArray() {
+	b := fp.AppendArray(nil, []fp.Decimal{fp.FromInt(1), fp.FromFloat(-0.5)})
+	fmt.Println(string(b))
+	// Output: [1,-0.5]
+}
+
+func ExampleFromEnv() {
+	os.Setenv("EXAMPLE_MAX_FEE", "1_000.50")
+
+	v, err := fp.FromEnv("EXAMPLE_MAX_FEE", fp.FromInt(100))
+	fmt.Println(v, err)
+	// Output: 1000.5 <nil>
+}
@@ -0,0 +1,28 @@
+package fp3
+
+import (
+	"github.com/nikolaydubina/fpdecimal"
+	"github.com/nikolaydubina/fpdecimal/fp6"
+)
+
+// fp6 has 3 more fractional digits
+const fp6Multiplier = 1000
+
+// FromFP6 rounds to 3 fractional digits.
+func FromFP6(v fp6.Decimal, mode fpdecimal.RoundingMode) Decimal {
+	return Decimal{fpdecimal.DivRound(v.Scaled(), fp6Multiplier, mode)}
+}
+
+// FP6 returns same value with 6 fractional digits.
+// Returns error when value does not fit, since range of fp6 is smaller.
+func (a Decimal) FP6() (fp6.Decimal, error) {
+	v, err := fpdecimal.MulDivRound(a.v, fp6Multiplier, 1, fpdecimal.ToZero)
+	return fp6.FromIntScaled(v), err
+}
+
+// MulFP6 multiplies by value with 6 fractional digits, such as amount by price, and rounds result.
+// Returns error when result does not fit.
+func (a Decimal) MulFP6(b fp6.Decimal, mode fpdecimal.RoundingMode) (Decimal, error) {
+	v, err := fpdecimal.MulDivRound(a.v, b.Scaled(), 1_000_000, mode)
+	return Decimal{v}, err
+}
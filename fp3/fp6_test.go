@@ -0,0 +1,109 @@
+package fp3_test
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/nikolaydubina/fpdecimal"
+	fp "github.com/nikolaydubina/fpdecimal/fp3"
+	"github.com/nikolaydubina/fpdecimal/fp6"
+)
+
+func TestFromFP6(t *testing.T) {
+	tests := []struct {
+		v    string
+		mode fpdecimal.RoundingMode
+		s    string
+	}{
+		{"1.0005", fpdecimal.ToNearestEven, "1"},
+		{"1.0015", fpdecimal.ToNearestEven, "1.002"},
+		{"1.0005", fpdecimal.ToNearestAway, "1.001"},
+		{"-1.000999", fpdecimal.ToZero, "-1"},
+		{"-1.000001", fpdecimal.AwayFromZero, "-1.001"},
+		{"-1.000001", fpdecimal.ToNegativeInf, "-1.001"},
+		{"-1.000001", fpdecimal.ToPositiveInf, "-1"},
+		{"9223372036854.775807", fpdecimal.ToNearestEven, "9223372036854.776"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.v+" "+tc.mode.String(), func(t *testing.T) {
+			v, _ := fp6.FromString(tc.v)
+			if s := fp.FromFP6(v, tc.mode).String(); s != tc.s {
+				t.Error(s, tc.s)
+			}
+		})
+	}
+}
+
+func TestDecimal_FP6(t *testing.T) {
+	v, _ := fp.FromString("-9223372036854.775")
+	if q, err := v.FP6(); err != nil || q.String() != "-9223372036854.775" {
+		t.Error(q, err)
+	}
+
+	v, _ = fp.FromString("9223372036855")
+	if q, err := v.FP6(); err == nil || q != fp6.Zero {
+		t.Error(q, err)
+	}
+}
+
+func TestDecimal_MulFP6(t *testing.T) {
+	tests := []struct {
+		amount string
+		price  string
+		mode   fpdecimal.RoundingMode
+		s      string
+	}{
+		{"100", "1.234567", fpdecimal.ToNearestEven, "123.457"},
+		{"100", "1.234567", fpdecimal.ToZero, "123.456"},
+		{"-2.5", "0.0001", fpdecimal.ToNearestEven, "0"},
+		{"-2.5", "0.0001", fpdecimal.AwayFromZero, "-0.001"},
+		{"1000000000000000", "9.000001", fpdecimal.ToNearestEven, "9000001000000000"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.amount+"*"+tc.price, func(t *testing.T) {
+			a, _ := fp.FromString(tc.amount)
+			p, _ := fp6.FromString(tc.price)
+			v, err := a.MulFP6(p, tc.mode)
+			if err != nil {
+				t.Error(err)
+			}
+			if s := v.String(); s != tc.s {
+				t.Error(s, tc.s)
+			}
+		})
+	}
+
+	v, err := fp.FromInt(math.MaxInt64/1000).MulFP6(fp6.FromInt(2), fpdecimal.ToZero)
+	if err == nil || v != fp.Zero {
+		t.Error(v, err)
+	}
+}
+
+func FuzzFP6(f *testing.F) {
+	f.Add(int64(0))
+	f.Add(int64(-123456))
+	f.Add(int64(math.MaxInt64 / 1000))
+	f.Fuzz(func(t *testing.T, a int64) {
+		v := fp.FromIntScaled(a)
+		q, err := v.FP6()
+		if err != nil {
+			if a <= math.MaxInt64/1000 && a >= math.MinInt64/1000 {
+				t.Error(a, err)
+			}
+			return
+		}
+		if w := fp.FromFP6(q, fpdecimal.ToZero); w != v {
+			t.Error(v, q, w)
+		}
+	})
+}
+
+func ExampleDecimal_MulFP6() {
+	amount, _ := fp.FromString("3")
+	price, _ := fp6.FromString("0.333333")
+
+	total, err := amount.MulFP6(price, fpdecimal.ToNearestEven)
+	fmt.Println(total, err)
+	// Output: 1 <nil>
+}
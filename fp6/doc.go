@@ -0,0 +1,4 @@
+// Package fp6 is fixed-point decimal of 6 fractional digits.
+// Conversion from fp3 is fp3.Decimal.FP6, and to fp3 is fp3.FromFP6.
+// They are in fp3, since fp3 imports fp6 and importing fp3 here would be import cycle.
+package fp6
@@ -0,0 +1,184 @@
+package fp6_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/nikolaydubina/fpdecimal"
+	"github.com/nikolaydubina/fpdecimal/fp3"
+	fp "github.com/nikolaydubina/fpdecimal/fp6"
+)
+
+func ExampleDecimal() {
+	var BuySP500Price = fp.FromInt(9000)
+
+	input := []byte(`{"sp500": 9000.000023}`)
+
+	type Stocks struct {
+		SP500 fp.Decimal `json:"sp500"`
+	}
+	var v Stocks
+	if err := json.Unmarshal(input, &v); err != nil {
+		log.Fatal(err)
+	}
+
+	var amountToBuy fp.Decimal
+	if v.SP500.GreaterThan(BuySP500Price) {
+		amountToBuy = amountToBuy.Add(v.SP500.Mul(fp.FromInt(2)))
+	}
+
+	fmt.Println(amountToBuy)
+	// Output: 18000.000046
+}
+
+func ExampleDecimal_skip_whole_fraction() {
+	v, _ := fp.FromString("1013.0000000")
+	fmt.Println(v)
+	// Output: 1013
+}
+
+func ExampleDecimal_skip_trailing_zeros() {
+	v, _ := fp.FromString("102.0000020")
+	fmt.Println(v)
+	// Output: 102.000002
+}
+
+func ExampleDecimal_Div() {
+	x, _ := fp.FromString("1.000000")
+	p := x.Div(fp.FromInt(3))
+	fmt.Print(p)
+	// Output: 0.333333
+}
+
+func ExampleDecimal_Div_whole() {
+	x, _ := fp.FromString("1.000000")
+	p := x.Div(fp.FromInt(5))
+	fmt.Print(p)
+	// Output: 0.2
+}
+
+func ExampleDecimal_Mod() {
+	x, _ := fp.FromString("1.000000")
+	m := x.Mod(fp.FromInt(3))
+	fmt.Print(m)
+	// Output: 0.000001
+}
+
+func ExampleDecimal_DivMod() {
+	x, _ := fp.FromString("1.000000")
+	p, m := x.DivMod(fp.FromInt(3))
+	fmt.Print(p, m)
+	// Output: 0.333333 0.000001
+}
+
+func ExampleDecimal_DivMod_whole() {
+	x, _ := fp.FromString("1.000000")
+	p, m := x.DivMod(fp.FromInt(5))
+	fmt.Print(p, m)
+	// Output: 0.2 0
+}
+
+func ExampleFromInt_uint8() {
+	var x uint8 = 100
+	v := fp.FromInt(x)
+	fmt.Print(v)
+	// Output: 100
+}
+
+func ExampleFromInt_int8() {
+	var x int8 = -100
+	v := fp.FromInt(x)
+	fmt.Print(v)
+	// Output: -100
+}
+
+func ExampleFromInt_int() {
+	var x int = -100
+	v := fp.FromInt(x)
+	fmt.Print(v)
+	// Output: -100
+}
+
+func ExampleFromInt_uint() {
+	var x uint = 100
+	v := fp.FromInt(x)
+	fmt.Print(v)
+	// Output: 100
+}
+
+func ExampleMin() {
+	min := fp.Min(fp.FromInt(100), fp.FromFloat(0.999999), fp.FromFloat(100.000001))
+	fmt.Print(min)
+	// Output: 0.999999
+}
+
+func ExampleMin_empty() {
+	defer func() { fmt.Print(recover()) }()
+	fp.Min()
+	// Output: min of empty set is undefined
+}
+
+func ExampleMax() {
+	max := fp.Max(fp.FromInt(100), fp.FromFloat(0.999999), fp.FromFloat(100.000001))
+	fmt.Print(max)
+	// Output: 100.000001
+}
+
+func ExampleMax_empty() {
+	defer func() { fmt.Print(recover()) }()
+	fp.Max()
+	// Output: max of empty set is undefined
+}
+
+func ExampleDecimal_UnmarshalCBOR() {
+	// 273.15 as in RFC 8949
+	var v fp.Decimal
+	err := v.UnmarshalCBOR([]byte{0xc4, 0x82, 0x21, 0x19, 0x6a, 0xb3})
+	fmt.Println(v, err)
+	// Output: 273.15 <nil>
+}
+
+func ExampleDecodeArray() {
+	prices := strings.NewReader(`[1.23, 4.56, "7.89", -0.001]`)
+
+	total := fp.Zero
+	for v, err := range fp.DecodeArray(prices) {
+		if err != nil {
+			log.Fatal(err)
+		}
+		total = total.Add(v)
+	}
+
+	fmt.Println(total)
+	// Output: 13.679
+}
+
+func ExampleAppendArray() {
+	b := fp.AppendArray(nil, []fp.Decimal{fp.FromInt(1), fp.FromFloat(-0.5)})
+	fmt.Println(string(b))
+	// Output: [1,-0.5]
+}
+
+func ExampleFromEnv() {
+	os.Setenv("EXAMPLE_MAX_FEE", "1_000.50")
+
+	v, err := fp.FromEnv("EXAMPLE_MAX_FEE", fp.FromInt(100))
+	fmt.Println(v, err)
+	// Output: 1000.5 <nil>
+}
+
+func Example_fromFP3() {
+	amount, _ := fp3.FromString("12.345")
+
+	v, err := amount.FP6()
+	fmt.Println(v, err)
+
+	back := fp3.FromFP6(v.Add(fp.FromIntScaled(500)), fpdecimal.ToNearestEven)
+	fmt.Println(back)
+	// Output:
+	// 12.345 <nil>
+	// 12.346
+}
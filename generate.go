@@ -0,0 +1,14 @@
+package fpdecimal
+
+//go:generate go run ./cmd/fpgen -pkg fp0 -digits 0 -o fp0
+//go:generate go run ./cmd/fpgen -pkg fp1 -digits 1 -o fp1
+//go:generate go run ./cmd/fpgen -pkg fp2 -digits 2 -o fp2
+//go:generate go run ./cmd/fpgen -pkg fp3 -digits 3 -o fp3
+//go:generate go run ./cmd/fpgen -pkg fp4 -digits 4 -o fp4
+//go:generate go run ./cmd/fpgen -pkg fp6 -digits 6 -o fp6
+//go:generate go run ./cmd/fpgen -pkg fp8 -digits 8 -o fp8
+//go:generate go run ./cmd/fpgen -pkg fp9 -digits 9 -o fp9
+//go:generate go run ./cmd/fpgen -pkg fp12 -digits 12 -o fp12
+//go:generate go run ./cmd/fpgen -pkg fp18 -digits 18 -o fp18
+//go:generate go run ./cmd/fpgen -unsigned -pkg ufp3 -digits 3 -o ufp3
+//go:generate go run ./cmd/fpgen -unsigned -pkg ufp6 -digits 6 -o ufp6
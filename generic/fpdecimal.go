@@ -0,0 +1,174 @@
+// Package generic is experimental fixed-point decimal parameterized by scale type.
+// This allows to write library code once for all precisions, while mixing of scales is compile error.
+//
+//	type Cents [2]struct{}
+//
+//	var price = generic.FromIntScaled[Cents](1099)
+//
+// Scale is zero-size array of length of fractional digits, length is the only source of scale.
+// Go calls methods of type parameters through dictionary, but length of array is constant of instantiation.
+// This way multiplier is constant and arithmetics compiles to same code as in fp3.
+package generic
+
+import (
+	"github.com/nikolaydubina/fpdecimal"
+)
+
+// Scale is number of fractional digits, as zero-size array of that length.
+type Scale interface {
+	~[0]struct{} | ~[1]struct{} | ~[2]struct{} | ~[3]struct{} | ~[4]struct{} | ~[5]struct{} | ~[6]struct{} |
+		~[7]struct{} | ~[8]struct{} | ~[9]struct{} | ~[10]struct{} | ~[11]struct{} | ~[12]struct{} |
+		~[13]struct{} | ~[14]struct{} | ~[15]struct{} | ~[16]struct{} | ~[17]struct{} | ~[18]struct{}
+}
+
+type (
+	FP0  [0]struct{}
+	FP1  [1]struct{}
+	FP2  [2]struct{}
+	FP3  [3]struct{}
+	FP4  [4]struct{}
+	FP6  [6]struct{}
+	FP8  [8]struct{}
+	FP9  [9]struct{}
+	FP12 [12]struct{}
+	FP18 [18]struct{}
+)
+
+// Decimal with fractional digits of scale S.
+// Fractions lower than that are discarded in operations.
+// Zero value is zero.
+type Decimal[S Scale] struct{ v int64 }
+
+type integer interface {
+	int | int8 | int16 | int32 | int64 | uint | uint8 | uint16 | uint32 | uint64
+}
+
+// fractionDigits is constant of instantiation.
+func fractionDigits[S Scale]() uint8 {
+	var s S
+	return uint8(len(s))
+}
+
+// multiplier is constant of instantiation, multiplications are folded once inlined.
+func multiplier[S Scale]() int64 {
+	var s S
+	m := int64(1)
+	if len(s)&1 != 0 {
+		m *= 10
+	}
+	if len(s)&2 != 0 {
+		m *= 100
+	}
+	if len(s)&4 != 0 {
+		m *= 10_000
+	}
+	if len(s)&8 != 0 {
+		m *= 100_000_000
+	}
+	if len(s)&16 != 0 {
+		m *= 10_000_000_000_000_000
+	}
+	return m
+}
+
+func FromInt[S Scale, T integer](v T) Decimal[S] { return Decimal[S]{int64(v) * multiplier[S]()} }
+
+func FromFloat[S Scale, T float32 | float64](v T) Decimal[S] {
+	return Decimal[S]{int64(float64(v) * float64(multiplier[S]()))}
+}
+
+// FromIntScaled expects value already scaled to minor units
+func FromIntScaled[S Scale, T integer](v T) Decimal[S] { return Decimal[S]{int64(v)} }
+
+func FromString[S Scale](s string) (Decimal[S], error) {
+	v, err := fpdecimal.ParseFixedPointDecimal([]byte(s), fractionDigits[S]())
+	return Decimal[S]{v}, err
+}
+
+func (v *Decimal[S]) UnmarshalJSON(b []byte) (err error) {
+	v.v, err = fpdecimal.ParseFixedPointDecimal(b, fractionDigits[S]())
+	return err
+}
+
+func (v Decimal[S]) MarshalJSON() ([]byte, error) { return v.Append(nil), nil }
+
+// UnmarshalText decodes text, surrounding whitespace and quotes are ignored, underscores can separate digits.
+func (v *Decimal[S]) UnmarshalText(b []byte) (err error) {
+	v.v, err = fpdecimal.ParseFixedPointDecimalText(b, fractionDigits[S]())
+	return err
+}
+
+func (v Decimal[S]) MarshalText() ([]byte, error) { return v.Append(nil), nil }
+
+func (a Decimal[S]) Scaled() int64 { return a.v }
+
+func (a Decimal[S]) Float32() float32 { return float32(a.v) / float32(multiplier[S]()) }
+
+func (a Decimal[S]) Float64() float64 { return float64(a.v) / float64(multiplier[S]()) }
+
+// Append appends formatted decimal to destination buffer.
+func (a Decimal[S]) Append(b []byte) []byte {
+	return fpdecimal.AppendFixedPointDecimal(b, a.v, fractionDigits[S]())
+}
+
+func (a Decimal[S]) String() string {
+	return fpdecimal.FixedPointDecimalToString(a.v, fractionDigits[S]())
+}
+
+func (a Decimal[S]) Add(b Decimal[S]) Decimal[S] { return Decimal[S]{v: a.v + b.v} }
+
+func (a Decimal[S]) Sub(b Decimal[S]) Decimal[S] { return Decimal[S]{v: a.v - b.v} }
+
+func (a Decimal[S]) Mul(b Decimal[S]) Decimal[S] { return Decimal[S]{v: a.v * b.v / multiplier[S]()} }
+
+func (a Decimal[S]) Div(b Decimal[S]) Decimal[S] { return Decimal[S]{v: a.v * multiplier[S]() / b.v} }
+
+func (a Decimal[S]) Mod(b Decimal[S]) Decimal[S] { return Decimal[S]{v: a.v % (b.v / multiplier[S]())} }
+
+func (a Decimal[S]) DivMod(b Decimal[S]) (part, remainder Decimal[S]) { return a.Div(b), a.Mod(b) }
+
+func (a Decimal[S]) Equal(b Decimal[S]) bool { return a.v == b.v }
+
+func (a Decimal[S]) GreaterThan(b Decimal[S]) bool { return a.v > b.v }
+
+func (a Decimal[S]) LessThan(b Decimal[S]) bool { return a.v < b.v }
+
+func (a Decimal[S]) GreaterThanOrEqual(b Decimal[S]) bool { return a.v >= b.v }
+
+func (a Decimal[S]) LessThanOrEqual(b Decimal[S]) bool { return a.v <= b.v }
+
+func (a Decimal[S]) Compare(b Decimal[S]) int {
+	if a.LessThan(b) {
+		return -1
+	}
+	if a.GreaterThan(b) {
+		return 1
+	}
+	return 0
+}
+
+func Min[S Scale](vs ...Decimal[S]) Decimal[S] {
+	if len(vs) == 0 {
+		panic("min of empty set is undefined")
+	}
+	var v Decimal[S] = vs[0]
+	for _, q := range vs {
+		if q.LessThan(v) {
+			v = q
+		}
+	}
+	return v
+}
+
+func Max[S Scale](vs ...Decimal[S]) Decimal[S] {
+	if len(vs) == 0 {
+		panic("max of empty set is undefined")
+	}
+	var v Decimal[S] = vs[0]
+	for _, q := range vs {
+		if q.GreaterThan(v) {
+			v = q
+		}
+	}
+	return v
+}
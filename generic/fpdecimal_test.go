@@ -0,0 +1,343 @@
+package generic_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"testing"
+	"unsafe"
+
+	"github.com/nikolaydubina/fpdecimal/fp18"
+	"github.com/nikolaydubina/fpdecimal/fp3"
+	"github.com/nikolaydubina/fpdecimal/generic"
+)
+
+// cents is scale defined outside of package.
+type cents [2]struct{}
+
+// digits counts fractional digits of smallest positive value.
+func digits[S generic.Scale]() uint8 {
+	s := generic.FromIntScaled[S](1).String()
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		return uint8(len(s) - i - 1)
+	}
+	return 0
+}
+
+func TestScale(t *testing.T) {
+	tests := []struct {
+		digits uint8
+		got    uint8
+		one    int64
+	}{
+		{0, digits[generic.FP0](), generic.FromInt[generic.FP0](1).Scaled()},
+		{1, digits[generic.FP1](), generic.FromInt[generic.FP1](1).Scaled()},
+		{2, digits[generic.FP2](), generic.FromInt[generic.FP2](1).Scaled()},
+		{3, digits[generic.FP3](), generic.FromInt[generic.FP3](1).Scaled()},
+		{4, digits[generic.FP4](), generic.FromInt[generic.FP4](1).Scaled()},
+		{6, digits[generic.FP6](), generic.FromInt[generic.FP6](1).Scaled()},
+		{8, digits[generic.FP8](), generic.FromInt[generic.FP8](1).Scaled()},
+		{9, digits[generic.FP9](), generic.FromInt[generic.FP9](1).Scaled()},
+		{12, digits[generic.FP12](), generic.FromInt[generic.FP12](1).Scaled()},
+		{18, digits[generic.FP18](), generic.FromInt[generic.FP18](1).Scaled()},
+		{2, digits[cents](), generic.FromInt[cents](1).Scaled()},
+		{5, digits[[5]struct{}](), generic.FromInt[[5]struct{}](1).Scaled()},
+	}
+	for _, tc := range tests {
+		t.Run(fmt.Sprint(tc.digits), func(t *testing.T) {
+			if tc.got != tc.digits {
+				t.Error(tc.got)
+			}
+			if tc.one != int64(math.Pow10(int(tc.digits))) {
+				t.Error(tc.one)
+			}
+		})
+	}
+}
+
+func FuzzArithmetics_SameAsFP3(f *testing.F) {
+	tests := [][2]int64{
+		{1, 2},
+		{1, -5},
+		{1, 0},
+		{1100, -2},
+		{123456, 1000},
+		{math.MaxInt64, math.MinInt64},
+	}
+	for _, tc := range tests {
+		f.Add(tc[0], tc[1])
+	}
+	f.Fuzz(func(t *testing.T, a, b int64) {
+		ga, gb := generic.FromIntScaled[generic.FP3](a), generic.FromIntScaled[generic.FP3](b)
+		fa, fb := fp3.FromIntScaled(a), fp3.FromIntScaled(b)
+
+		v := []bool{
+			ga.Add(gb).Scaled() == fa.Add(fb).Scaled(),
+			ga.Sub(gb).Scaled() == fa.Sub(fb).Scaled(),
+			ga.Mul(gb).Scaled() == fa.Mul(fb).Scaled(),
+			ga.Compare(gb) == fa.Compare(fb),
+			ga.Equal(gb) == fa.Equal(fb),
+			ga.String() == fa.String(),
+			ga.Float64() == fa.Float64(),
+			generic.FromInt[generic.FP3](a).Scaled() == fp3.FromInt(a).Scaled(),
+		}
+		for i, q := range v {
+			if !q {
+				t.Error(i, a, b, ga, fa)
+			}
+		}
+
+		if b != 0 {
+			if ga.Div(gb).Scaled() != fa.Div(fb).Scaled() {
+				t.Error(a, b, ga.Div(gb), fa.Div(fb))
+			}
+		}
+		if b/1000 != 0 {
+			p, r := ga.DivMod(gb)
+			fp, fr := fa.DivMod(fb)
+			if p.Scaled() != fp.Scaled() || r.Scaled() != fr.Scaled() {
+				t.Error(a, b, p, r, fp, fr)
+			}
+		}
+	})
+}
+
+func FuzzArithmetics_SameAsFP18(f *testing.F) {
+	tests := [][2]int64{
+		{1, 2},
+		{1, -5},
+		{1_000_000_000_000_000_000, 3},
+		{math.MaxInt64, math.MinInt64},
+	}
+	for _, tc := range tests {
+		f.Add(tc[0], tc[1])
+	}
+	f.Fuzz(func(t *testing.T, a, b int64) {
+		ga, gb := generic.FromIntScaled[generic.FP18](a), generic.FromIntScaled[generic.FP18](b)
+		fa, fb := fp18.FromIntScaled(a), fp18.FromIntScaled(b)
+
+		v := []bool{
+			ga.Add(gb).Scaled() == fa.Add(fb).Scaled(),
+			ga.Mul(gb).Scaled() == fa.Mul(fb).Scaled(),
+			ga.String() == fa.String(),
+		}
+		for i, q := range v {
+			if !q {
+				t.Error(i, a, b, ga, fa)
+			}
+		}
+
+		if b != 0 {
+			if ga.Div(gb).Scaled() != fa.Div(fb).Scaled() {
+				t.Error(a, b, ga.Div(gb), fa.Div(fb))
+			}
+		}
+	})
+}
+
+func FuzzParse_SameAsFP3(f *testing.F) {
+	tests := []string{
+		"123.456",
+		"0.1",
+		"-0.01",
+		"0..1",
+		"1_000.5",
+		"",
+	}
+	for _, tc := range tests {
+		f.Add(tc)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		g, gerr := generic.FromString[generic.FP3](s)
+		v, err := fp3.FromString(s)
+		if g.Scaled() != v.Scaled() || (gerr == nil) != (err == nil) {
+			t.Error(s, g, v, gerr, err)
+		}
+
+		var gt generic.Decimal[generic.FP3]
+		var vt fp3.Decimal
+		gerr, err = gt.UnmarshalText([]byte(s)), vt.UnmarshalText([]byte(s))
+		if gt.Scaled() != vt.Scaled() || (gerr == nil) != (err == nil) {
+			t.Error(s, gt, vt, gerr, err)
+		}
+	})
+}
+
+func TestJSON(t *testing.T) {
+	type Order struct {
+		Price    generic.Decimal[generic.FP3] `json:"price"`
+		Quantity generic.Decimal[generic.FP6] `json:"quantity"`
+	}
+
+	var v Order
+	if err := json.Unmarshal([]byte(`{"price": 9000.001, "quantity": 0.000005}`), &v); err != nil {
+		t.Error(err)
+	}
+	if v.Price != generic.FromIntScaled[generic.FP3](9000001) || v.Quantity != generic.FromIntScaled[generic.FP6](5) {
+		t.Error(v)
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil || string(b) != `{"price":9000.001,"quantity":0.000005}` {
+		t.Error(string(b), err)
+	}
+
+	if err := json.Unmarshal([]byte(`{"price": "9000"}`), &v); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestDecimalMemoryLayout(t *testing.T) {
+	a, _ := generic.FromString[generic.FP18]("-1000.123")
+	if v := unsafe.Sizeof(a); v != 8 {
+		t.Error(a, v)
+	}
+}
+
+func TestMinMax(t *testing.T) {
+	type D = generic.Decimal[generic.FP2]
+	vs := []D{generic.FromInt[generic.FP2](1), generic.FromIntScaled[generic.FP2](-1), {}}
+	if v := generic.Min(vs...); v != generic.FromIntScaled[generic.FP2](-1) {
+		t.Error(v)
+	}
+	if v := generic.Max(vs...); v != generic.FromInt[generic.FP2](1) {
+		t.Error(v)
+	}
+}
+
+func BenchmarkParse(b *testing.B) {
+	s := []byte("123456.789")
+
+	b.Run("generic", func(b *testing.B) {
+		var v generic.Decimal[generic.FP3]
+		for n := 0; n < b.N; n++ {
+			if err := v.UnmarshalJSON(s); err != nil {
+				b.Error(err)
+			}
+		}
+	})
+
+	b.Run("fp3", func(b *testing.B) {
+		var v fp3.Decimal
+		for n := 0; n < b.N; n++ {
+			if err := v.UnmarshalJSON(s); err != nil {
+				b.Error(err)
+			}
+		}
+	})
+}
+
+func BenchmarkPrint(b *testing.B) {
+	var s string
+
+	b.Run("generic", func(b *testing.B) {
+		v := generic.FromIntScaled[generic.FP3](-123456789)
+		for n := 0; n < b.N; n++ {
+			s = v.String()
+		}
+	})
+
+	b.Run("fp3", func(b *testing.B) {
+		v := fp3.FromIntScaled(-123456789)
+		for n := 0; n < b.N; n++ {
+			s = v.String()
+		}
+	})
+
+	if s == "" {
+		b.Error("empty str")
+	}
+}
+
+func BenchmarkArithmetic(b *testing.B) {
+	b.Run("generic", func(b *testing.B) {
+		x, _ := generic.FromString[generic.FP3]("251.231")
+		y := generic.FromInt[generic.FP3](2).Add(generic.FromIntScaled[generic.FP3](1))
+		var s, u generic.Decimal[generic.FP3]
+
+		b.Run("add", func(b *testing.B) {
+			for n := 0; n < b.N; n++ {
+				s = x.Add(y)
+			}
+		})
+
+		b.Run("mul", func(b *testing.B) {
+			for n := 0; n < b.N; n++ {
+				s = x.Mul(y)
+			}
+		})
+
+		b.Run("div", func(b *testing.B) {
+			for n := 0; n < b.N; n++ {
+				s = x.Div(y)
+			}
+		})
+
+		b.Run("divmod", func(b *testing.B) {
+			for n := 0; n < b.N; n++ {
+				s, u = x.DivMod(y)
+			}
+		})
+
+		if s == (generic.Decimal[generic.FP3]{}) || u == (generic.Decimal[generic.FP3]{}) {
+			b.Error()
+		}
+	})
+
+	b.Run("fp3", func(b *testing.B) {
+		x, _ := fp3.FromString("251.231")
+		y := fp3.FromInt(2).Add(fp3.FromIntScaled(1))
+		var s, u fp3.Decimal
+
+		b.Run("add", func(b *testing.B) {
+			for n := 0; n < b.N; n++ {
+				s = x.Add(y)
+			}
+		})
+
+		b.Run("mul", func(b *testing.B) {
+			for n := 0; n < b.N; n++ {
+				s = x.Mul(y)
+			}
+		})
+
+		b.Run("div", func(b *testing.B) {
+			for n := 0; n < b.N; n++ {
+				s = x.Div(y)
+			}
+		})
+
+		b.Run("divmod", func(b *testing.B) {
+			for n := 0; n < b.N; n++ {
+				s, u = x.DivMod(y)
+			}
+		})
+
+		if s == fp3.Zero || u == fp3.Zero {
+			b.Error()
+		}
+	})
+}
+
+// Sum is written once for all scales.
+func Sum[S generic.Scale](vs ...generic.Decimal[S]) (s generic.Decimal[S]) {
+	for _, v := range vs {
+		s = s.Add(v)
+	}
+	return s
+}
+
+func ExampleDecimal() {
+	var order struct {
+		Prices     []generic.Decimal[generic.FP2] `json:"prices"`
+		Quantities []generic.Decimal[generic.FP8] `json:"quantities"`
+	}
+	if err := json.Unmarshal([]byte(`{"prices": [10.5, 0.25], "quantities": [0.00000001, 1.5]}`), &order); err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println(Sum(order.Prices...), Sum(order.Quantities...))
+	// Output: 10.75 1.50000001
+}
@@ -1,3 +1,3 @@
 module github.com/nikolaydubina/fpdecimal
 
-go 1.21
+go 1.23
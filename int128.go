@@ -0,0 +1,265 @@
+package fpdecimal
+
+import "math/bits"
+
+// Wide values are two's complement of high and low bits, as in ParseFixedPointDecimalWide.
+// Their magnitudes are high and low bits of unsigned 128 bits.
+
+// absWide returns magnitude and sign of two's complement of high and low bits.
+func absWide(hi int64, lo uint64) (uhi, ulo uint64, neg bool) {
+	if hi >= 0 {
+		return uint64(hi), lo, false
+	}
+	uhi, ulo = negWide(uint64(hi), lo)
+	return uhi, ulo, true
+}
+
+// signedWide converts magnitude and sign to two's complement of high and low bits.
+func signedWide(uhi, ulo uint64, neg bool) (hi int64, lo uint64, err error) {
+	if uhi > 1<<63 || (uhi == 1<<63 && (ulo != 0 || !neg)) {
+		return 0, 0, errOverflow
+	}
+	if neg {
+		uhi, ulo = negWide(uhi, ulo)
+	}
+	return int64(uhi), ulo, nil
+}
+
+func negWide(hi, lo uint64) (uint64, uint64) {
+	lo, c := bits.Sub64(0, lo, 0)
+	hi, _ = bits.Sub64(0, hi, c)
+	return hi, lo
+}
+
+// quoRemWide returns quotient and remainder of hi:lo by d.
+// Panics when d is zero, as integer division.
+func quoRemWide(hi, lo, d uint64) (qhi, qlo, r uint64) {
+	qhi, r = bits.Div64(0, hi, d)
+	qlo, r = bits.Div64(r, lo, d)
+	return qhi, qlo, r
+}
+
+// lessWide is true when a is less than b.
+func lessWide(ahi, alo, bhi, blo uint64) bool { return ahi < bhi || (ahi == bhi && alo < blo) }
+
+// pow10Wide returns 10^e for e from 0 to 38, since 10^38 is largest that fits 128 bits.
+func pow10Wide(e int) (hi, lo uint64) {
+	lo = 1
+	for e > 0 {
+		k := min(e, len(pow10)-1)
+		hi, lo, _ = mulAdd128(hi, lo, uint64(pow10[k]), 0, false)
+		e -= k
+	}
+	return hi, lo
+}
+
+// scaleExpWide is scaleExpUint of 128-bit magnitude.
+func scaleExpWide(hi, lo uint64, e int, p uint8) (uint64, uint64, error) {
+	if hi == 0 && lo == 0 {
+		return 0, 0, nil
+	}
+
+	// 128 bits are at most 39 digits, so this is bound of exponents of non-zero values
+	const maxDigits = 39
+
+	e += int(p)
+	switch {
+	case e < -maxDigits:
+		return 0, 0, errPrecision
+	case e > maxDigits:
+		return 0, 0, errOverflow
+	}
+
+	for e < 0 {
+		k := min(-e, len(pow10)-1)
+		qhi, qlo, r := quoRemWide(hi, lo, uint64(pow10[k]))
+		if r != 0 {
+			return 0, 0, errPrecision
+		}
+		hi, lo, e = qhi, qlo, e+k
+	}
+
+	var ovf bool
+	for e > 0 {
+		k := min(e, len(pow10)-1)
+		hi, lo, ovf = mulAdd128(hi, lo, uint64(pow10[k]), 0, ovf)
+		e -= k
+	}
+	if ovf {
+		return 0, 0, errOverflow
+	}
+	return hi, lo, nil
+}
+
+// DivRoundWide returns two's complement of high and low bits divided by d, rounded by mode.
+// Panics when d is zero, as integer division.
+func DivRoundWide(hi int64, lo uint64, d uint64, mode RoundingMode) (int64, uint64) {
+	uhi, ulo, neg := absWide(hi, lo)
+	qhi, qlo, r := quoRemWide(uhi, ulo, d)
+	if mode.roundUp(qlo, r, d, neg) {
+		var c uint64
+		qlo, c = bits.Add64(qlo, 1, 0)
+		qhi += c
+	}
+	// magnitude of quotient is at most 2^127, which is min when negative
+	if neg {
+		qhi, qlo = negWide(qhi, qlo)
+	}
+	return int64(qhi), qlo
+}
+
+// mulWide returns 256 bits of a×b, most significant word first.
+func mulWide(ahi, alo, bhi, blo uint64) [4]uint64 {
+	var r [4]uint64
+	var c uint64
+
+	r[2], r[3] = bits.Mul64(alo, blo)
+
+	h, l := bits.Mul64(ahi, blo)
+	r[2], c = bits.Add64(r[2], l, 0)
+	r[1], c = bits.Add64(h, 0, c)
+
+	h, l = bits.Mul64(alo, bhi)
+	r[2], c = bits.Add64(r[2], l, 0)
+	r[1], c = bits.Add64(r[1], h, c)
+	r[0] = c
+
+	h, l = bits.Mul64(ahi, bhi)
+	r[1], c = bits.Add64(r[1], l, 0)
+	r[0] += h + c
+
+	return r
+}
+
+// quoRem256 returns quotient and remainder of 256 bits u, most significant word first, by dhi:dlo.
+// Quotient has to fit 128 bits, that is u[0]:u[1] is less than d.
+func quoRem256(u [4]uint64, dhi, dlo uint64) (qhi, qlo, rhi, rlo uint64) {
+	if dhi == 0 {
+		_, r := bits.Div64(u[0], u[1], dlo)
+		qhi, r = bits.Div64(r, u[2], dlo)
+		qlo, r = bits.Div64(r, u[3], dlo)
+		return qhi, qlo, 0, r
+	}
+
+	// Knuth, The Art of Computer Programming, Vol. 2, 4.3.1, Algorithm D
+	// normalized u[0]:u[1] is still less than normalized d, so quotient has two digits
+	s := uint(bits.LeadingZeros64(dhi))
+	dh, dl := dhi<<s|dlo>>(64-s), dlo<<s
+	u3, u2 := u[0]<<s|u[1]>>(64-s), u[1]<<s|u[2]>>(64-s)
+	u1, u0 := u[2]<<s|u[3]>>(64-s), u[3]<<s
+
+	var r1, r0 uint64
+	qhi, r1, r0 = divStepWide(u3, u2, u1, dh, dl)
+	qlo, r1, r0 = divStepWide(r1, r0, u0, dh, dl)
+	return qhi, qlo, r1 >> s, r0>>s | r1<<(64-s)
+}
+
+// divStepWide divides n2:n1:n0 by normalized dh:dl, when n2:n1 is less than dh:dl.
+// Returns quotient digit and remainder.
+func divStepWide(n2, n1, n0, dh, dl uint64) (q, r1, r0 uint64) {
+	var rhat, c uint64
+	if n2 >= dh {
+		q = 1<<64 - 1
+		rhat, c = bits.Add64(n1, dh, 0)
+	} else {
+		q, rhat = bits.Div64(n2, n1, dh)
+	}
+
+	for c == 0 {
+		ph, pl := bits.Mul64(q, dl)
+		if ph < rhat || (ph == rhat && pl <= n0) {
+			break
+		}
+		q--
+		rhat, c = bits.Add64(rhat, dh, 0)
+	}
+
+	ph, pl := bits.Mul64(q, dl)
+	qh, ql := bits.Mul64(q, dh)
+	p1, c := bits.Add64(ql, ph, 0)
+	p2 := qh + c
+
+	var b uint64
+	r0, b = bits.Sub64(n0, pl, 0)
+	r1, b = bits.Sub64(n1, p1, b)
+	_, b = bits.Sub64(n2, p2, b)
+	if b != 0 {
+		q--
+		r0, c = bits.Add64(r0, dl, 0)
+		r1, _ = bits.Add64(r1, dh, c)
+	}
+	return q, r1, r0
+}
+
+// roundUpWide is roundUp of 128-bit remainder rhi:rlo and divisor dhi:dlo.
+func (m RoundingMode) roundUpWide(q, rhi, rlo, dhi, dlo uint64, neg bool) bool {
+	if rhi == 0 && rlo == 0 {
+		return false
+	}
+
+	// only comparison of remainder to half of divisor matters, so it is kept by small numbers
+	var r, d uint64 = 1, 3
+	if rhi>>63 != 0 {
+		r = 2
+	} else if thi, tlo := rhi<<1|rlo>>63, rlo<<1; thi == dhi && tlo == dlo {
+		r, d = 1, 2
+	} else if lessWide(dhi, dlo, thi, tlo) {
+		r = 2
+	}
+	return m.roundUp(q, r, d, neg)
+}
+
+// MulDivRoundWide is MulDivRound for two's complement of high and low bits.
+// Product is 256 bits, so it does not overflow unless result does not fit 128 bits.
+func MulDivRoundWide(ahi int64, alo uint64, bhi int64, blo uint64, chi int64, clo uint64, mode RoundingMode) (hi int64, lo uint64, err error) {
+	if chi == 0 && clo == 0 {
+		return 0, 0, errDivisionByZero
+	}
+
+	uahi, ualo, na := absWide(ahi, alo)
+	ubhi, ublo, nb := absWide(bhi, blo)
+	uchi, uclo, nc := absWide(chi, clo)
+	neg := na != nb != nc
+
+	qhi, qlo, err := mulDivRoundWide(uahi, ualo, ubhi, ublo, uchi, uclo, neg, mode)
+	if err != nil {
+		return 0, 0, err
+	}
+	return signedWide(qhi, qlo, neg)
+}
+
+// mulDivRoundWide returns magnitude of a×b/c of 128-bit magnitudes, rounded by mode as of sign neg.
+func mulDivRoundWide(ahi, alo, bhi, blo, chi, clo uint64, neg bool, mode RoundingMode) (qhi, qlo uint64, err error) {
+	u := mulWide(ahi, alo, bhi, blo)
+	if !lessWide(u[0], u[1], chi, clo) {
+		return 0, 0, errOverflow
+	}
+
+	qhi, qlo, rhi, rlo := quoRem256(u, chi, clo)
+	if mode.roundUpWide(qlo, rhi, rlo, chi, clo, neg) {
+		var c uint64
+		qlo, c = bits.Add64(qlo, 1, 0)
+		qhi, c = bits.Add64(qhi, 0, c)
+		if c != 0 {
+			return 0, 0, errOverflow
+		}
+	}
+	return qhi, qlo, nil
+}
+
+// FixedPointDecimalFromWide converts two's complement of high and low bits of p fractions
+// into fixed-point decimal of q fractions, rounded by mode.
+// Fractions q are at most p, and p is at most 38.
+// Returns error when value does not fit int64.
+func FixedPointDecimalFromWide(hi int64, lo uint64, p, q uint8, mode RoundingMode) (int64, error) {
+	uhi, ulo, neg := absWide(hi, lo)
+	dhi, dlo := pow10Wide(int(p - q))
+	vhi, v, err := mulDivRoundWide(uhi, ulo, 0, 1, dhi, dlo, neg, mode)
+	if err != nil {
+		return 0, err
+	}
+	if vhi != 0 {
+		return 0, errOverflow
+	}
+	return signed(v, neg)
+}
@@ -0,0 +1,223 @@
+// Package gen generates fixed-point decimal packages from templates, signed and unsigned.
+package gen
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"go/format"
+	"go/token"
+	"math"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/nikolaydubina/fpdecimal"
+)
+
+//go:embed *.tmpl
+var templates embed.FS
+
+// MaxDigits is largest number of fractional digits, since multiplier has to fit int64.
+const MaxDigits = 18
+
+// Config of generated package.
+type Config struct {
+	Package  string // name of package
+	Type     string // name of type
+	Digits   uint8  // number of fractional digits
+	Import   string // import path of package, used in tests
+	Unsigned bool   // uint64 that can never be negative
+}
+
+// Packages of this module, each in directory of same name.
+var Packages = []Config{
+	pkg("fp0", 0),
+	pkg("fp1", 1),
+	pkg("fp2", 2),
+	pkg("fp3", 3),
+	pkg("fp4", 4),
+	pkg("fp6", 6),
+	pkg("fp8", 8),
+	pkg("fp9", 9),
+	pkg("fp12", 12),
+	pkg("fp18", 18),
+	upkg("ufp3", 3),
+	upkg("ufp6", 6),
+}
+
+func pkg(name string, digits uint8) Config {
+	return Config{Package: name, Type: "Decimal", Digits: digits, Import: "github.com/nikolaydubina/fpdecimal/" + name}
+}
+
+func upkg(name string, digits uint8) Config {
+	c := pkg(name, digits)
+	c.Unsigned = true
+	return c
+}
+
+// Command of fpgen that generates package.
+func (c Config) Command() string {
+	s := "fpgen"
+	if c.Type != "Decimal" {
+		s += " -type " + c.Type
+	}
+	if c.Unsigned {
+		s += " -unsigned"
+	}
+	return s + " -pkg " + c.Package + " -digits " + strconv.Itoa(int(c.Digits))
+}
+
+func (c Config) validate() error {
+	if !token.IsIdentifier(c.Package) {
+		return fmt.Errorf("package %q is not identifier", c.Package)
+	}
+	if !token.IsIdentifier(c.Type) {
+		return fmt.Errorf("type %q is not identifier", c.Type)
+	}
+	if c.Digits > MaxDigits {
+		return fmt.Errorf("digits %d is more than %d", c.Digits, MaxDigits)
+	}
+	if c.Import == "" {
+		return errors.New("missing import path")
+	}
+	return nil
+}
+
+// files maps template to generated file name.
+func (c Config) files() map[string]string {
+	if c.Unsigned {
+		return map[string]string{
+			"ufpdecimal.go.tmpl":      "fpdecimal.go",
+			"ufpdecimal_test.go.tmpl": "fpdecimal_test.go",
+		}
+	}
+	return map[string]string{
+		"fpdecimal.go.tmpl":      "fpdecimal.go",
+		"fpdecimal_test.go.tmpl": "fpdecimal_test.go",
+	}
+}
+
+// Files returns formatted source files of package by file name.
+func Files(c Config) (map[string][]byte, error) {
+	if err := c.validate(); err != nil {
+		return nil, err
+	}
+
+	t, err := template.New("").Funcs(funcs(c.Digits)).ParseFS(templates, "*.tmpl")
+	if err != nil {
+		return nil, err
+	}
+
+	files := c.files()
+	out := make(map[string][]byte, len(files))
+	for name, file := range files {
+		var b bytes.Buffer
+		if err := t.ExecuteTemplate(&b, name, data{Config: c}); err != nil {
+			return nil, err
+		}
+		src, err := format.Source(b.Bytes())
+		if err != nil {
+			return nil, err
+		}
+		out[file] = src
+	}
+	return out, nil
+}
+
+type data struct{ Config }
+
+// Multiplier is Go literal of 10^Digits, digits are separated by underscores when there are many.
+func (d data) Multiplier() string {
+	s := "1" + strings.Repeat("0", int(d.Digits))
+	if len(s) <= 4 {
+		return s
+	}
+	var b []byte
+	for i := range len(s) {
+		if i > 0 && (len(s)-i)%3 == 0 {
+			b = append(b, '_')
+		}
+		b = append(b, s[i])
+	}
+	return string(b)
+}
+
+func (d data) Max() string {
+	if d.Unsigned {
+		return "+" + fpdecimal.FixedPointDecimalUintToString(math.MaxUint64, d.Digits)
+	}
+	return "+" + fpdecimal.FixedPointDecimalToString(math.MaxInt64, d.Digits)
+}
+
+func (d data) Min() string {
+	if d.Unsigned {
+		return "0"
+	}
+	return fpdecimal.FixedPointDecimalToString(math.MinInt64, d.Digits)
+}
+
+// MaxFloat is bound of floats that print exactly in Digits fractions, since float64 has 15 significant digits.
+func (d data) MaxFloat() string {
+	if d.Digits <= 7 {
+		return "1e8"
+	}
+	return "1e" + strconv.Itoa(15-int(d.Digits))
+}
+
+// funcs format values scaled to p fractions, so that same template has values of any precision.
+func funcs(p uint8) template.FuncMap {
+	return template.FuncMap{
+		// str is decimal of scaled value, as printed by package
+		"str": func(v int64) string { return fpdecimal.FixedPointDecimalToString(v, p) },
+		// fixed is decimal of scaled value with all fractional digits, as fmt %.3f
+		"fixed": func(v int64) string { return fixed(v, p) },
+		// text is decimal of scaled value with digits separated by underscores
+		"text": func(v int64) string { return text(fixed(v, p)) },
+	}
+}
+
+func fixed(v int64, p uint8) string {
+	s := strconv.FormatInt(v, 10)
+	neg := v < 0
+	if neg {
+		s = s[1:]
+	}
+	if len(s) <= int(p) {
+		s = strings.Repeat("0", int(p)-len(s)+1) + s
+	}
+	if p > 0 {
+		s = s[:len(s)-int(p)] + "." + s[len(s)-int(p):]
+	}
+	if neg {
+		s = "-" + s
+	}
+	return s
+}
+
+// text groups digits by three from decimal point, trailing zeros are removed.
+func text(s string) string {
+	whole, frac, _ := strings.Cut(s, ".")
+	frac = strings.TrimRight(frac, "0")
+
+	var b []byte
+	for i := range len(whole) {
+		if i > 0 && isDigit(whole[i-1]) && (len(whole)-i)%3 == 0 {
+			b = append(b, '_')
+		}
+		b = append(b, whole[i])
+	}
+	if frac != "" {
+		b = append(b, '.')
+	}
+	for i := range len(frac) {
+		if i > 0 && i%3 == 0 {
+			b = append(b, '_')
+		}
+		b = append(b, frac[i])
+	}
+	return string(b)
+}
+
+func isDigit(ch byte) bool { return '0' <= ch && ch <= '9' }
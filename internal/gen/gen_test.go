@@ -0,0 +1,30 @@
+package gen_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nikolaydubina/fpdecimal/internal/gen"
+)
+
+func TestPackages_UpToDate(t *testing.T) {
+	for _, c := range gen.Packages {
+		t.Run(c.Package, func(t *testing.T) {
+			files, err := gen.Files(c)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for name, src := range files {
+				b, err := os.ReadFile(filepath.Join("..", "..", c.Package, name))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !bytes.Equal(b, src) {
+					t.Errorf("%s/%s is not up to date, run go generate", c.Package, name)
+				}
+			}
+		})
+	}
+}
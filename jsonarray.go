@@ -0,0 +1,186 @@
+package fpdecimal
+
+import (
+	"fmt"
+	"io"
+	"iter"
+)
+
+const jsonArrayBufferSize = 4096
+
+var (
+	errJSONArrayStart = &errorString{"json array: expected '['"}
+	errJSONArrayDelim = &errorString{"json array: expected ',' or ']'"}
+	errJSONArrayValue = &errorString{"json array: expected number"}
+	errJSONArrayEnd   = &errorString{"json array: unexpected end of data"}
+)
+
+// DecodeJSONArray decodes JSON array of numbers into fixed-point decimals of p fractions.
+// Numbers in strings are accepted too.
+// Data is read in chunks, so arrays of any size can be decoded without holding them in memory.
+// Reading stops after end of array.
+// Decoding stops at first error.
+func DecodeJSONArray(r io.Reader, p uint8) iter.Seq2[int64, error] {
+	return DecodeJSONArrayFunc(r, func(s []byte) (int64, error) { return ParseFixedPointDecimal(s, p) })
+}
+
+// DecodeJSONArrayUint is DecodeJSONArray for uint64, negative value is error.
+func DecodeJSONArrayUint(r io.Reader, p uint8) iter.Seq2[uint64, error] {
+	return DecodeJSONArrayFunc(r, func(s []byte) (uint64, error) { return ParseFixedPointDecimalUint(s, p) })
+}
+
+// DecodeJSONArrayFunc is DecodeJSONArray that parses numbers by parse, such as ParseFixedPointDecimalWide.
+// Contents of number are valid only during call of parse.
+func DecodeJSONArrayFunc[T any](r io.Reader, parse func(s []byte) (T, error)) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		s := jsonArrayScanner{r: r, buf: make([]byte, jsonArrayBufferSize)}
+
+		if c, err := s.next(); err != nil {
+			yield(zero, err)
+			return
+		} else if c != '[' {
+			yield(zero, errJSONArrayStart)
+			return
+		}
+		s.pos++
+
+		c, err := s.next()
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+		if c == ']' {
+			return
+		}
+
+		for i := 0; ; i++ {
+			token, err := s.token()
+			if err != nil {
+				yield(zero, fmt.Errorf("element %d: %w", i, err))
+				return
+			}
+
+			v, err := parse(token)
+			if err != nil {
+				yield(zero, fmt.Errorf("element %d: %w", i, err))
+				return
+			}
+
+			if !yield(v, nil) {
+				return
+			}
+
+			c, err := s.next()
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+			s.pos++
+			switch c {
+			case ',':
+			case ']':
+				return
+			default:
+				yield(zero, fmt.Errorf("element %d: %w", i, errJSONArrayDelim))
+				return
+			}
+		}
+	}
+}
+
+type jsonArrayScanner struct {
+	r   io.Reader
+	buf []byte
+	pos int
+	end int
+	err error
+}
+
+// fill reads more data, keeping unread data.
+// Returns false when no more data.
+func (s *jsonArrayScanner) fill() bool {
+	if s.err != nil {
+		return false
+	}
+
+	n := copy(s.buf, s.buf[s.pos:s.end])
+	s.pos, s.end = 0, n
+	if s.end == len(s.buf) {
+		s.buf = append(s.buf, make([]byte, len(s.buf))...)
+	}
+
+	for {
+		n, err := s.r.Read(s.buf[s.end:])
+		s.end += n
+		if err != nil {
+			s.err = err
+		}
+		if n > 0 || err != nil {
+			return n > 0
+		}
+	}
+}
+
+// next skips whitespace and returns next byte without consuming it.
+func (s *jsonArrayScanner) next() (byte, error) {
+	for {
+		for ; s.pos < s.end; s.pos++ {
+			switch c := s.buf[s.pos]; c {
+			case ' ', '\t', '\n', '\r':
+			default:
+				return c, nil
+			}
+		}
+		if !s.fill() {
+			return 0, s.eof()
+		}
+	}
+}
+
+// token consumes number or string, and returns its contents.
+// Contents are valid until next read.
+func (s *jsonArrayScanner) token() ([]byte, error) {
+	c, err := s.next()
+	if err != nil {
+		return nil, err
+	}
+
+	quoted := c == '"'
+	if quoted {
+		s.pos++
+	}
+
+	for i := s.pos; ; i++ {
+		if i == s.end {
+			start := s.pos
+			if !s.fill() {
+				return nil, s.eof()
+			}
+			i -= start
+		}
+
+		switch c := s.buf[i]; {
+		case quoted && c == '"':
+			token := s.buf[s.pos:i]
+			s.pos = i + 1
+			return token, nil
+		case !quoted && (c == ',' || c == ']' || c == ' ' || c == '\t' || c == '\n' || c == '\r'):
+			token := s.buf[s.pos:i]
+			s.pos = i
+			if len(token) == 0 {
+				return nil, errJSONArrayValue
+			}
+			return token, nil
+		case quoted && c == '\\':
+			return nil, errJSONArrayValue
+		}
+	}
+}
+
+func (s *jsonArrayScanner) eof() error {
+	if s.err == io.EOF {
+		return errJSONArrayEnd
+	}
+	return s.err
+}
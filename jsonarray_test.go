@@ -0,0 +1,253 @@
+package fpdecimal_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/nikolaydubina/fpdecimal"
+)
+
+func decodeJSONArray(r io.Reader, p uint8) (vs []int64, err error) {
+	for v, err := range fpdecimal.DecodeJSONArray(r, p) {
+		if err != nil {
+			return vs, err
+		}
+		vs = append(vs, v)
+	}
+	return vs, nil
+}
+
+func TestDecodeJSONArray(t *testing.T) {
+	tests := []struct {
+		s  string
+		vs []int64
+	}{
+		{`[]`, nil},
+		{` [ ] `, nil},
+		{`[1]`, []int64{1000}},
+		{`[1.23, 4.56,-0.001]`, []int64{1230, 4560, -1}},
+		{"\n[\n\t1 ,\r\n2\n]\n", []int64{1000, 2000}},
+		{`["1.5", 2]`, []int64{1500, 2000}},
+		{`[1] trailing data is not read`, []int64{1000}},
+		{`[9223372036854775.807]`, []int64{9223372036854775807}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.s, func(t *testing.T) {
+			for _, r := range []io.Reader{strings.NewReader(tc.s), iotest.OneByteReader(strings.NewReader(tc.s))} {
+				vs, err := decodeJSONArray(r, 3)
+				if err != nil {
+					t.Error(err)
+				}
+				if fmt.Sprint(vs) != fmt.Sprint(tc.vs) {
+					t.Error(vs, tc.vs)
+				}
+			}
+		})
+	}
+}
+
+func TestDecodeJSONArrayUint(t *testing.T) {
+	var vs []uint64
+	for v, err := range fpdecimal.DecodeJSONArrayUint(strings.NewReader(`[1.5, "0", 18446744073709551.615, -1, 2]`), 3) {
+		if err != nil {
+			if err.Error() != "element 3: negative value" {
+				t.Error(err)
+			}
+			break
+		}
+		vs = append(vs, v)
+	}
+	if fmt.Sprint(vs) != "[1500 0 18446744073709551615]" {
+		t.Error(vs)
+	}
+}
+
+func TestDecodeJSONArrayFunc(t *testing.T) {
+	parse := func(s []byte) (string, error) {
+		hi, lo, err := fpdecimal.ParseFixedPointDecimalWide(s, 18)
+		if err != nil {
+			return "", err
+		}
+		return fpdecimal.FixedPointDecimalWideToString(hi, lo, 18), nil
+	}
+
+	var vs []string
+	for v, err := range fpdecimal.DecodeJSONArrayFunc(strings.NewReader(`[1.5, "-18.446744073709551616", 170141183460469231731.687303715884105728, 2]`), parse) {
+		if err != nil {
+			if err.Error() != "element 2: overflow" {
+				t.Error(err)
+			}
+			if v != "" {
+				t.Error(v)
+			}
+			break
+		}
+		vs = append(vs, v)
+	}
+	if fmt.Sprint(vs) != "[1.5 -18.446744073709551616]" {
+		t.Error(vs)
+	}
+}
+
+func TestDecodeJSONArray_Error(t *testing.T) {
+	tests := []struct {
+		s   string
+		vs  []int64
+		err string
+	}{
+		{``, nil, "json array: unexpected end of data"},
+		{` `, nil, "json array: unexpected end of data"},
+		{`{}`, nil, "json array: expected '['"},
+		{`[`, nil, "json array: unexpected end of data"},
+		{`[1`, nil, "element 0: json array: unexpected end of data"},
+		{`[1,`, []int64{1000}, "element 1: json array: unexpected end of data"},
+		{`[1,]`, []int64{1000}, "element 1: json array: expected number"},
+		{`[,1]`, nil, "element 0: json array: expected number"},
+		{`[1 2]`, []int64{1000}, "element 0: json array: expected ',' or ']'"},
+		{`[1, "2`, []int64{1000}, "element 1: json array: unexpected end of data"},
+		{`[1, ""]`, []int64{1000}, "element 1: empty string"},
+		{`[1, null]`, []int64{1000}, "element 1: bad digit"},
+		{`[1, 2x]`, []int64{1000}, "element 1: bad digit"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.s, func(t *testing.T) {
+			vs, err := decodeJSONArray(strings.NewReader(tc.s), 3)
+			if err == nil || err.Error() != tc.err {
+				t.Error(err)
+			}
+			if fmt.Sprint(vs) != fmt.Sprint(tc.vs) {
+				t.Error(vs, tc.vs)
+			}
+		})
+	}
+}
+
+func TestDecodeJSONArray_ReaderError(t *testing.T) {
+	errRead := errors.New("read")
+	r := io.MultiReader(strings.NewReader(`[1, 2`), iotest.ErrReader(errRead))
+
+	vs, err := decodeJSONArray(r, 3)
+	if !errors.Is(err, errRead) {
+		t.Error(err)
+	}
+	if fmt.Sprint(vs) != "[1000]" {
+		t.Error(vs)
+	}
+}
+
+func TestDecodeJSONArray_Break(t *testing.T) {
+	var vs []int64
+	for v, err := range fpdecimal.DecodeJSONArray(strings.NewReader(`[1, 2, 3]`), 3) {
+		if err != nil {
+			t.Error(err)
+		}
+		vs = append(vs, v)
+		if len(vs) == 2 {
+			break
+		}
+	}
+	if fmt.Sprint(vs) != "[1000 2000]" {
+		t.Error(vs)
+	}
+}
+
+func TestDecodeJSONArray_Large(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("[")
+	for i := 0; i < 10_000; i++ {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		b.WriteString(strings.Repeat("0", i%100) + "1.5")
+	}
+	b.WriteString("]")
+
+	vs, err := decodeJSONArray(strings.NewReader(b.String()), 3)
+	if err != nil {
+		t.Error(err)
+	}
+	if len(vs) != 10_000 {
+		t.Error(len(vs))
+	}
+	for _, v := range vs {
+		if v != 1500 {
+			t.Fatal(v)
+		}
+	}
+}
+
+func FuzzDecodeJSONArray(f *testing.F) {
+	tests := []string{
+		`[]`,
+		`[1.23, 4.56,-0.001]`,
+		`["1.5", 2]`,
+		`[1 2]`,
+	}
+	for _, tc := range tests {
+		f.Add(tc)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		vs, err := decodeJSONArray(strings.NewReader(s), 3)
+		if err != nil {
+			return
+		}
+
+		var b []byte
+		b = append(b, '[')
+		for i, v := range vs {
+			if i > 0 {
+				b = append(b, ',')
+			}
+			b = fpdecimal.AppendFixedPointDecimal(b, v, 3)
+		}
+		b = append(b, ']')
+
+		q, err := decodeJSONArray(iotest.OneByteReader(strings.NewReader(string(b))), 3)
+		if err != nil || fmt.Sprint(q) != fmt.Sprint(vs) {
+			t.Error(s, string(b), vs, q, err)
+		}
+	})
+}
+
+func jsonArrayForBenchmark() string {
+	var b strings.Builder
+	b.WriteString("[")
+	for i := 0; i < 1000; i++ {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(testsFloats[0].vals[i%len(testsFloats[0].vals)])
+	}
+	b.WriteString("]")
+	return b.String()
+}
+
+func BenchmarkDecodeJSONArray(b *testing.B) {
+	s := jsonArrayForBenchmark()
+	b.ResetTimer()
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		for _, err := range fpdecimal.DecodeJSONArray(strings.NewReader(s), 3) {
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkDecodeJSONArray_float_json_Unmarshal(b *testing.B) {
+	s := []byte(jsonArrayForBenchmark())
+	b.ResetTimer()
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		var vs []float64
+		if err := json.Unmarshal(s, &vs); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
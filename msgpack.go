@@ -0,0 +1,177 @@
+package fpdecimal
+
+import "math"
+
+// MsgpackExtDecimal is MessagePack extension type of decimals.
+// Payload is exponent as int8 followed by mantissa as big-endian two's complement integer of 1 to 8 bytes,
+// or of 1 to 16 bytes for 128 bits.
+const MsgpackExtDecimal int8 = 'd'
+
+// MessagePack formats, https://github.com/msgpack/msgpack/blob/master/spec.md
+const (
+	msgpackFixExt1 = 0xd4
+	msgpackFixExt8 = 0xd7
+	msgpackExt8    = 0xc7
+	msgpackUint8   = 0xcc
+	msgpackUint64  = 0xcf
+	msgpackInt8    = 0xd0
+	msgpackInt64   = 0xd3
+)
+
+var (
+	errMsgpackType         = &errorString{"msgpack: not a decimal or integer"}
+	errMsgpackShort        = &errorString{"msgpack: unexpected end of data"}
+	errMsgpackTrailingData = &errorString{"msgpack: trailing data"}
+)
+
+// AppendMsgpackDecimal appends fixed-point decimal of p fractions as MessagePack extension MsgpackExtDecimal.
+// Exponent is always -p, so that mantissa is the same as v.
+func AppendMsgpackDecimal(b []byte, v int64, p uint8) []byte {
+	return append(b,
+		msgpackExt8, 9, byte(MsgpackExtDecimal),
+		byte(-int8(p)),
+		byte(v>>56), byte(v>>48), byte(v>>40), byte(v>>32), byte(v>>24), byte(v>>16), byte(v>>8), byte(v),
+	)
+}
+
+// AppendMsgpackDecimalWide is AppendMsgpackDecimal for 128 bits, mantissa is 16 bytes.
+func AppendMsgpackDecimalWide(b []byte, hi int64, lo uint64, p uint8) []byte {
+	return append(b,
+		msgpackExt8, 17, byte(MsgpackExtDecimal),
+		byte(-int8(p)),
+		byte(hi>>56), byte(hi>>48), byte(hi>>40), byte(hi>>32), byte(hi>>24), byte(hi>>16), byte(hi>>8), byte(hi),
+		byte(lo>>56), byte(lo>>48), byte(lo>>40), byte(lo>>32), byte(lo>>24), byte(lo>>16), byte(lo>>8), byte(lo),
+	)
+}
+
+// AppendMsgpackDecimalUint is AppendMsgpackDecimal for uint64.
+// Value beyond int64 has 16 bytes of mantissa, as in AppendMsgpackDecimalWide.
+func AppendMsgpackDecimalUint(b []byte, v uint64, p uint8) []byte {
+	if v > math.MaxInt64 {
+		return AppendMsgpackDecimalWide(b, 0, v, p)
+	}
+	return AppendMsgpackDecimal(b, int64(v), p)
+}
+
+// ParseMsgpackDecimal parses single MessagePack extension MsgpackExtDecimal into fixed-point decimal of p fractions.
+// Integers are accepted too.
+// Returns error when value does not fit or has more than p fractions.
+func ParseMsgpackDecimal(b []byte, p uint8) (int64, error) {
+	hi, lo, e, err := parseMsgpackDecimal(b, 8)
+	if err != nil {
+		return 0, err
+	}
+	if hi != int64(lo)>>63 {
+		return 0, errOverflow
+	}
+	return scaleExp(int64(lo), e, p)
+}
+
+// ParseMsgpackDecimalWide is ParseMsgpackDecimal for 128 bits.
+func ParseMsgpackDecimalWide(b []byte, p uint8) (hi int64, lo uint64, err error) {
+	mhi, mlo, e, err := parseMsgpackDecimal(b, 16)
+	if err != nil {
+		return 0, 0, err
+	}
+	uhi, ulo, neg := absWide(mhi, mlo)
+	uhi, ulo, err = scaleExpWide(uhi, ulo, e, p)
+	if err != nil {
+		return 0, 0, err
+	}
+	return signedWide(uhi, ulo, neg)
+}
+
+// ParseMsgpackDecimalUint is ParseMsgpackDecimal for uint64, negative value is error.
+// Mantissa can be up to 16 bytes, as written by AppendMsgpackDecimalUint.
+func ParseMsgpackDecimalUint(b []byte, p uint8) (uint64, error) {
+	mhi, mlo, e, err := parseMsgpackDecimal(b, 16)
+	if err != nil {
+		return 0, err
+	}
+	uhi, ulo, neg := absWide(mhi, mlo)
+	uhi, ulo, err = scaleExpWide(uhi, ulo, e, p)
+	if err != nil {
+		return 0, err
+	}
+	if uhi != 0 {
+		return 0, errOverflow
+	}
+	return unsigned(ulo, neg)
+}
+
+// parseMsgpackDecimal returns mantissa as two's complement of high and low bits, and exponent.
+// Mantissa of extension is at most size bytes.
+func parseMsgpackDecimal(b []byte, size int) (hi int64, lo uint64, e int, err error) {
+	if len(b) == 0 {
+		return 0, 0, 0, errMsgpackShort
+	}
+
+	var h, n int // header and data lengths
+	switch c := b[0]; {
+	case c <= 0x7f || c >= 0xe0:
+		if len(b) > 1 {
+			return 0, 0, 0, errMsgpackTrailingData
+		}
+		v := int64(int8(c))
+		return v >> 63, uint64(v), 0, nil
+	case c >= msgpackUint8 && c <= msgpackInt64:
+		hi, lo, err := parseMsgpackInt(b)
+		return hi, lo, 0, err
+	case c >= msgpackFixExt1 && c <= msgpackFixExt8:
+		h, n = 2, 1<<(c-msgpackFixExt1)
+	case c == msgpackExt8:
+		if len(b) < 2 {
+			return 0, 0, 0, errMsgpackShort
+		}
+		h, n = 3, int(b[1])
+	default:
+		return 0, 0, 0, errMsgpackType
+	}
+
+	if len(b) < h+n {
+		return 0, 0, 0, errMsgpackShort
+	}
+	if len(b) > h+n {
+		return 0, 0, 0, errMsgpackTrailingData
+	}
+	if int8(b[h-1]) != MsgpackExtDecimal || n < 2 || n > size+1 {
+		return 0, 0, 0, errMsgpackType
+	}
+	payload := b[h:]
+
+	hi, lo, err = ParseTwosComplementWide(payload[1:])
+	return hi, lo, int(int8(payload[0])), err
+}
+
+// parseMsgpackInt returns integer as two's complement of high and low bits.
+func parseMsgpackInt(b []byte) (hi int64, lo uint64, err error) {
+	c := b[0]
+	signed := c >= msgpackInt8
+	if signed {
+		c -= msgpackInt8
+	} else {
+		c -= msgpackUint8
+	}
+
+	n := 1 << c
+	if len(b) < n+1 {
+		return 0, 0, errMsgpackShort
+	}
+	if len(b) > n+1 {
+		return 0, 0, errMsgpackTrailingData
+	}
+
+	var u uint64
+	for _, q := range b[1:] {
+		u = u<<8 | uint64(q)
+	}
+
+	if !signed {
+		return 0, u, nil
+	}
+
+	// sign extend
+	s := 64 - 8*uint(n)
+	v := int64(u<<s) >> s
+	return v >> 63, uint64(v), nil
+}
@@ -0,0 +1,249 @@
+package fpdecimal_test
+
+import (
+	"encoding/hex"
+	"math"
+	"testing"
+
+	"github.com/nikolaydubina/fpdecimal"
+)
+
+func TestAppendMsgpackDecimal(t *testing.T) {
+	tests := []struct {
+		v int64
+		p uint8
+		b string
+	}{
+		{0, 3, "c70964fd0000000000000000"},
+		{-1, 3, "c70964fdffffffffffffffff"},
+		{273150, 3, "c70964fd0000000000042afe"},
+		{1500, 0, "c709640000000000000005dc"},
+		{-9223372036854775808, 6, "c70964fa8000000000000000"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.b, func(t *testing.T) {
+			b := fpdecimal.AppendMsgpackDecimal(nil, tc.v, tc.p)
+			if s := hex.EncodeToString(b); s != tc.b {
+				t.Error(s, tc.b)
+			}
+
+			v, err := fpdecimal.ParseMsgpackDecimal(b, tc.p)
+			if err != nil {
+				t.Error(err)
+			}
+			if v != tc.v {
+				t.Error(v, tc.v)
+			}
+		})
+	}
+}
+
+func TestParseMsgpackDecimal(t *testing.T) {
+	tests := []struct {
+		b string
+		p uint8
+		v int64
+	}{
+		{"d56401ff", 3, -10000},
+		{"d664fe006ab3", 3, 273150},
+		{"d764fd00000000042afe", 3, 273150},
+		{"c70264fe05", 3, 50},
+		{"05", 3, 5000},
+		{"ff", 3, -1000},
+		{"cc80", 3, 128000},
+		{"cd0100", 3, 256000},
+		{"d0ff", 3, -1000},
+		{"d1ff00", 3, -256000},
+		{"d2ffffff00", 3, -256000},
+		{"d3ffffffffffffff00", 3, -256000},
+		{"cf00000000000f4240", 0, 1000000},
+	}
+	for _, tc := range tests {
+		t.Run(tc.b, func(t *testing.T) {
+			b, _ := hex.DecodeString(tc.b)
+			v, err := fpdecimal.ParseMsgpackDecimal(b, tc.p)
+			if err != nil {
+				t.Error(err)
+			}
+			if v != tc.v {
+				t.Error(v, tc.v)
+			}
+		})
+	}
+}
+
+func TestParseMsgpackDecimal_Error(t *testing.T) {
+	tests := []struct {
+		b   string
+		err string
+	}{
+		{"", "msgpack: unexpected end of data"},
+		{"c7", "msgpack: unexpected end of data"},
+		{"c70964fd00", "msgpack: unexpected end of data"},
+		{"cd01", "msgpack: unexpected end of data"},
+		{"d56401ff00", "msgpack: trailing data"},
+		{"cd010000", "msgpack: trailing data"},
+		{"01ffff", "msgpack: trailing data"},
+		{"ff00", "msgpack: trailing data"},
+		{"d40105", "msgpack: not a decimal or integer"},
+		{"d56501ff", "msgpack: not a decimal or integer"},
+		{"c70a64fd000000000000000000", "msgpack: not a decimal or integer"},
+		{"c0", "msgpack: not a decimal or integer"},
+		{"cb3ff0000000000000", "msgpack: not a decimal or integer"},
+		{"d564fc01", "more fraction digits than supported"},
+		{"d56410ff", "overflow"},
+		{"cfffffffffffffffff", "overflow"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.b, func(t *testing.T) {
+			b, _ := hex.DecodeString(tc.b)
+			v, err := fpdecimal.ParseMsgpackDecimal(b, 3)
+			if err == nil || err.Error() != tc.err {
+				t.Error(err)
+			}
+			if v != 0 {
+				t.Error(v)
+			}
+		})
+	}
+}
+
+func FuzzParseMsgpackDecimal(f *testing.F) {
+	tests := []string{
+		"c70964fd0000000000042afe",
+		"d56401ff",
+		"d3ffffffffffffff00",
+		"05",
+	}
+	for _, tc := range tests {
+		b, _ := hex.DecodeString(tc)
+		f.Add(b)
+	}
+	f.Fuzz(func(t *testing.T, b []byte) {
+		v, err := fpdecimal.ParseMsgpackDecimal(b, 3)
+		if err != nil {
+			if v != 0 {
+				t.Error("has to be 0 on error")
+			}
+			return
+		}
+
+		q, err := fpdecimal.ParseMsgpackDecimal(fpdecimal.AppendMsgpackDecimal(nil, v, 3), 3)
+		if err != nil || q != v {
+			t.Error(v, q, err)
+		}
+	})
+}
+
+func TestMsgpackDecimalWide(t *testing.T) {
+	tests := []struct {
+		hi int64
+		lo uint64
+		p  uint8
+		b  string
+	}{
+		{0, 0, 18, "c71164ee" + "00000000000000000000000000000000"},
+		{1, 0, 0, "c7116400" + "00000000000000010000000000000000"},
+		{-1, math.MaxUint64 - 9999, 3, "c71164fd" + "ffffffffffffffffffffffffffffd8f0"},
+		{math.MaxInt64, math.MaxUint64, 18, "c71164ee" + "7fffffffffffffffffffffffffffffff"},
+		{math.MinInt64, 0, 18, "c71164ee" + "80000000000000000000000000000000"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.b, func(t *testing.T) {
+			b := fpdecimal.AppendMsgpackDecimalWide(nil, tc.hi, tc.lo, tc.p)
+			if s := hex.EncodeToString(b); s != tc.b {
+				t.Error(s, tc.b)
+			}
+			if hi, lo, err := fpdecimal.ParseMsgpackDecimalWide(b, tc.p); err != nil || hi != tc.hi || lo != tc.lo {
+				t.Error(hi, lo, err)
+			}
+		})
+	}
+
+	parse := []struct {
+		b  string
+		hi int64
+		lo uint64
+	}{
+		{"d56401ff", -1, math.MaxUint64 - 9999},
+		{"c70a64fd010000000000000000", 1, 0},
+		{"cfffffffffffffffff", 999, math.MaxUint64 - 999},
+		{"05", 0, 5000},
+	}
+	for _, tc := range parse {
+		t.Run(tc.b, func(t *testing.T) {
+			b, _ := hex.DecodeString(tc.b)
+			if hi, lo, err := fpdecimal.ParseMsgpackDecimalWide(b, 3); err != nil || hi != tc.hi || lo != tc.lo {
+				t.Error(hi, lo, err)
+			}
+		})
+	}
+
+	errs := []struct {
+		b   string
+		err string
+	}{
+		{"c7126400" + "0000000000000000000000000000000001", "msgpack: not a decimal or integer"},
+		{"c71164fc" + "00000000000000000000000000000001", "more fraction digits than supported"},
+		{"c7116401" + "7fffffffffffffffffffffffffffffff", "overflow"},
+		{"c71164fd" + "00000000", "msgpack: unexpected end of data"},
+	}
+	for _, tc := range errs {
+		t.Run(tc.b, func(t *testing.T) {
+			b, _ := hex.DecodeString(tc.b)
+			if hi, lo, err := fpdecimal.ParseMsgpackDecimalWide(b, 3); err == nil || err.Error() != tc.err || hi != 0 || lo != 0 {
+				t.Error(hi, lo, err)
+			}
+		})
+	}
+}
+
+func BenchmarkParseMsgpackDecimal(b *testing.B) {
+	d := fpdecimal.AppendMsgpackDecimal(nil, 123456789, 3)
+	for n := 0; n < b.N; n++ {
+		if v, err := fpdecimal.ParseMsgpackDecimal(d, 3); err != nil || v != 123456789 {
+			b.Error(v, err)
+		}
+	}
+}
+
+func TestMsgpackDecimalUint(t *testing.T) {
+	tests := []struct {
+		v uint64
+		b string
+	}{
+		{0, "c70964fd0000000000000000"},
+		{1000, "c70964fd00000000000003e8"},
+		{math.MaxInt64, "c70964fd7fffffffffffffff"},
+		{math.MaxUint64, "c71164fd" + "0000000000000000ffffffffffffffff"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.b, func(t *testing.T) {
+			b := fpdecimal.AppendMsgpackDecimalUint(nil, tc.v, 3)
+			if s := hex.EncodeToString(b); s != tc.b {
+				t.Error(s, tc.b)
+			}
+			if v, err := fpdecimal.ParseMsgpackDecimalUint(b, 3); err != nil || v != tc.v {
+				t.Error(v, err)
+			}
+		})
+	}
+
+	errs := []struct {
+		b   string
+		err string
+	}{
+		{"d564fdff", "negative value"},
+		{"c71164fd" + "00000000000000010000000000000000", "overflow"},
+		{"d564fc01", "more fraction digits than supported"},
+		{"c0", "msgpack: not a decimal or integer"},
+	}
+	for _, tc := range errs {
+		t.Run(tc.b, func(t *testing.T) {
+			b, _ := hex.DecodeString(tc.b)
+			if v, err := fpdecimal.ParseMsgpackDecimalUint(b, 3); err == nil || err.Error() != tc.err || v != 0 {
+				t.Error(v, err)
+			}
+		})
+	}
+}
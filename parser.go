@@ -1,5 +1,11 @@
 package fpdecimal
 
+import (
+	"bytes"
+	"math"
+	"math/bits"
+)
+
 const sep = '.'
 
 type errorString struct{ v string }
@@ -68,3 +74,324 @@ func ParseFixedPointDecimal(s []byte, p uint8) (int64, error) {
 
 	return n, nil
 }
+
+var errBadUnderscore = &errorString{"underscore must separate digits"}
+
+// ParseFixedPointDecimalText parses fixed-point decimal of p fractions as written by people in configuration.
+// Surrounding whitespace and quotes are ignored.
+// Underscores can separate digits, as in Go literals: 1_000.50.
+func ParseFixedPointDecimalText(s []byte, p uint8) (int64, error) {
+	var buf [64]byte
+	s, err := trimText(s, buf[:0])
+	if err != nil {
+		return 0, err
+	}
+	return ParseFixedPointDecimal(s, p)
+}
+
+// trimText removes surrounding whitespace and quotes, and underscores between digits.
+// Buffer is used when there are underscores.
+func trimText(s, buf []byte) ([]byte, error) {
+	s = bytes.TrimSpace(s)
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		s = bytes.TrimSpace(s[1 : len(s)-1])
+	}
+
+	if bytes.IndexByte(s, '_') < 0 {
+		return s, nil
+	}
+
+	b := buf
+	for i, ch := range s {
+		if ch != '_' {
+			b = append(b, ch)
+			continue
+		}
+		if i == 0 || i == len(s)-1 || !isDigit(s[i-1]) || !isDigit(s[i+1]) {
+			return nil, errBadUnderscore
+		}
+	}
+	return b, nil
+}
+
+func isDigit(ch byte) bool { return '0' <= ch && ch <= '9' }
+
+var errNegative = &errorString{"negative value"}
+
+// ParseFixedPointDecimalUint parses non-negative fixed-point decimal of p fractions into uint64.
+// Unlike int64, overflow is error.
+func ParseFixedPointDecimalUint(s []byte, p uint8) (uint64, error) {
+	if len(s) == 0 {
+		return 0, errEmptyString
+	}
+
+	if s[0] == '-' {
+		return 0, errNegative
+	}
+	if s[0] == '+' {
+		s = s[1:]
+		if len(s) < 1 {
+			return 0, errMissingDigitsAfterSign
+		}
+	}
+
+	const maxDiv10 = math.MaxUint64 / 10
+
+	var pn = int8(p)
+	var d int8 = -1 // current decimal position
+	var n uint64    // output
+	var ovf bool
+	for _, ch := range s {
+		if d == pn {
+			break
+		}
+
+		if ch == sep {
+			if d != -1 {
+				return 0, errMultipleDots
+			}
+			d = 0
+			continue
+		}
+
+		ch -= '0'
+		if ch > 9 {
+			return 0, errBadDigit
+		}
+		ovf = ovf || n > maxDiv10 || (n == maxDiv10 && ch > math.MaxUint64%10)
+		n = n*10 + uint64(ch)
+
+		if d != -1 {
+			d++
+		}
+	}
+
+	// fill rest of 0
+	if d == -1 {
+		d = 0
+	}
+	for i := d; i < pn; i++ {
+		ovf = ovf || n > maxDiv10
+		n = n * 10
+	}
+
+	if ovf {
+		return 0, errOverflow
+	}
+	return n, nil
+}
+
+// ParseFixedPointDecimalUintText is as ParseFixedPointDecimalText for uint64.
+func ParseFixedPointDecimalUintText(s []byte, p uint8) (uint64, error) {
+	var buf [64]byte
+	s, err := trimText(s, buf[:0])
+	if err != nil {
+		return 0, err
+	}
+	return ParseFixedPointDecimalUint(s, p)
+}
+
+// ParseFixedPointDecimalWide parses fixed-point decimal of p fractions into 128 bits.
+// Returns two's complement of high and low bits.
+func ParseFixedPointDecimalWide(s []byte, p uint8) (hi int64, lo uint64, err error) {
+	if len(s) == 0 {
+		return 0, 0, errEmptyString
+	}
+
+	s0 := s
+	if s[0] == '-' || s[0] == '+' {
+		s = s[1:]
+		if len(s) < 1 {
+			return 0, 0, errMissingDigitsAfterSign
+		}
+	}
+
+	var pn = int8(p)
+	var d int8 = -1 // current decimal position
+	var u, l uint64 // output, high and low bits
+	var ovf bool
+
+	// digits are collected in int64 first, as 128-bit multiplication is slower
+	var c uint64 // collected digits
+	var k int    // number of collected digits
+	for _, ch := range s {
+		if d == pn {
+			break
+		}
+
+		if ch == sep {
+			if d != -1 {
+				return 0, 0, errMultipleDots
+			}
+			d = 0
+			continue
+		}
+
+		ch -= '0'
+		if ch > 9 {
+			return 0, 0, errBadDigit
+		}
+		c = c*10 + uint64(ch)
+		k++
+		if k == len(pow10)-1 {
+			u, l, ovf = mulAdd128(u, l, uint64(pow10[k]), c, ovf)
+			c, k = 0, 0
+		}
+
+		if d != -1 {
+			d++
+		}
+	}
+	u, l, ovf = mulAdd128(u, l, uint64(pow10[k]), c, ovf)
+
+	// fill rest of 0
+	if d == -1 {
+		d = 0
+	}
+	for n := int(pn - d); n > 0; n -= k {
+		k = min(n, len(pow10)-1)
+		u, l, ovf = mulAdd128(u, l, uint64(pow10[k]), 0, ovf)
+	}
+
+	// magnitude of min is 2^127
+	if ovf || u > 1<<63 || (u == 1<<63 && (l != 0 || s0[0] != '-')) {
+		return 0, 0, errOverflow
+	}
+
+	if s0[0] == '-' {
+		var c uint64
+		l, c = bits.Sub64(0, l, 0)
+		u, _ = bits.Sub64(0, u, c)
+	}
+
+	return int64(u), l, nil
+}
+
+// ParseFixedPointDecimalWideText is as ParseFixedPointDecimalText for 128 bits.
+func ParseFixedPointDecimalWideText(s []byte, p uint8) (hi int64, lo uint64, err error) {
+	var buf [64]byte
+	s, err = trimText(s, buf[:0])
+	if err != nil {
+		return 0, 0, err
+	}
+	return ParseFixedPointDecimalWide(s, p)
+}
+
+// mulAdd128 returns hi:lo*m+a, overflow is kept.
+func mulAdd128(hi, lo, m, a uint64, ovf bool) (uint64, uint64, bool) {
+	h, l := bits.Mul64(lo, m)
+	o, hm := bits.Mul64(hi, m)
+	hi, c := bits.Add64(hm, h, 0)
+	ovf = ovf || o != 0 || c != 0
+	lo, c = bits.Add64(l, a, 0)
+	hi, c = bits.Add64(hi, 0, c)
+	return hi, lo, ovf || c != 0
+}
+
+var (
+	errMissingDigits   = &errorString{"missing digits"}
+	errMissingExponent = &errorString{"missing digits in exponent"}
+)
+
+// ParseFixedPointDecimalExp parses decimal with optional exponent, such as 1.25e-3, into fixed-point decimal of p fractions.
+// This is format of google.type.Decimal and JSON numbers.
+// Unlike ParseFixedPointDecimal, fractions are never discarded, instead error is returned. Trailing zeros are allowed.
+func ParseFixedPointDecimalExp(s []byte, p uint8) (int64, error) {
+	if len(s) == 0 {
+		return 0, errEmptyString
+	}
+
+	neg := s[0] == '-'
+	if s[0] == '-' || s[0] == '+' {
+		s = s[1:]
+	}
+
+	var m uint64  // significant digits, without trailing zeros
+	var zeros int // trailing zeros not yet added to m
+	var e int     // decimal exponent of m×10^zeros
+	var n int     // number of digits
+	var dot, ovf bool
+	i := 0
+	for ; i < len(s) && s[i] != 'e' && s[i] != 'E'; i++ {
+		if s[i] == sep {
+			if dot {
+				return 0, errMultipleDots
+			}
+			dot = true
+			continue
+		}
+
+		ch := s[i] - '0'
+		if ch > 9 {
+			return 0, errBadDigit
+		}
+		n++
+		if dot {
+			e--
+		}
+		if ch == 0 {
+			if m != 0 {
+				zeros++
+			}
+			continue
+		}
+		for ; zeros > 0 && !ovf; zeros-- {
+			ovf = m > math.MaxUint64/10
+			m *= 10
+		}
+		ovf = ovf || m > (math.MaxUint64-uint64(ch))/10
+		m = m*10 + uint64(ch)
+	}
+	if n == 0 {
+		return 0, errMissingDigits
+	}
+
+	if i < len(s) {
+		x, err := parseExponent(s[i+1:])
+		if err != nil {
+			return 0, err
+		}
+		e += x
+	}
+
+	if m == 0 {
+		return 0, nil
+	}
+	if ovf {
+		return 0, errOverflow
+	}
+
+	u, err := scaleExpUint(m, e+zeros, p)
+	if err != nil {
+		return 0, err
+	}
+	return signed(u, neg)
+}
+
+// parseExponent parses signed decimal exponent.
+// Exponents of large magnitude are clamped, since they overflow or have too many fractions anyway.
+func parseExponent(s []byte) (int, error) {
+	neg := len(s) > 0 && s[0] == '-'
+	if len(s) > 0 && (s[0] == '-' || s[0] == '+') {
+		s = s[1:]
+	}
+	if len(s) == 0 {
+		return 0, errMissingExponent
+	}
+
+	var e int
+	for _, ch := range s {
+		ch -= '0'
+		if ch > 9 {
+			return 0, errBadDigit
+		}
+		if e < 1_000_000 {
+			e = e*10 + int(ch)
+		}
+	}
+
+	if neg {
+		return -e, nil
+	}
+	return e, nil
+}
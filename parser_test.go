@@ -1,6 +1,8 @@
 package fpdecimal_test
 
 import (
+	"math"
+	"strings"
 	"testing"
 
 	"github.com/nikolaydubina/fpdecimal"
@@ -41,3 +43,366 @@ func FuzzParseFixedPointDecimal(f *testing.F) {
 		}
 	})
 }
+
+func TestParseFixedPointDecimalText(t *testing.T) {
+	tests := []struct {
+		s string
+		v int64
+	}{
+		{"1.5", 1500},
+		{"  1.5\n", 1500},
+		{`"1.5"`, 1500},
+		{`'-1.5'`, -1500},
+		{`" 1.5 "`, 1500},
+		{"1_000.50", 1000500},
+		{"-1_000_000", -1000000000},
+		{"0.000_1", 0},
+		{"0.1_2_3", 123},
+		{` "1_000" `, 1000000},
+		{"000_000_000_000_000_000_000_000_000_000_001.5", 1500},
+	}
+	for _, tc := range tests {
+		t.Run(tc.s, func(t *testing.T) {
+			v, err := fpdecimal.ParseFixedPointDecimalText([]byte(tc.s), 3)
+			if err != nil {
+				t.Error(err)
+			}
+			if v != tc.v {
+				t.Error(v, tc.v)
+			}
+		})
+	}
+}
+
+func TestParseFixedPointDecimalText_Error(t *testing.T) {
+	tests := []struct {
+		s   string
+		err string
+	}{
+		{"", "empty string"},
+		{"  ", "empty string"},
+		{`""`, "empty string"},
+		{`"1.5`, "bad digit"},
+		{`"1.5'`, "bad digit"},
+		{"1 000", "bad digit"},
+		{"_1", "underscore must separate digits"},
+		{"1_", "underscore must separate digits"},
+		{"1__0", "underscore must separate digits"},
+		{"1_.5", "underscore must separate digits"},
+		{"1._5", "underscore must separate digits"},
+		{"-_1", "underscore must separate digits"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.s, func(t *testing.T) {
+			v, err := fpdecimal.ParseFixedPointDecimalText([]byte(tc.s), 3)
+			if err == nil || err.Error() != tc.err {
+				t.Error(err)
+			}
+			if v != 0 {
+				t.Error(v)
+			}
+		})
+	}
+}
+
+func FuzzParseFixedPointDecimalText(f *testing.F) {
+	tests := []string{
+		"123.456",
+		"0.1",
+		"-0.01",
+		"0..1",
+		"123456",
+	}
+	for _, tc := range tests {
+		f.Add(tc)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		v, err := fpdecimal.ParseFixedPointDecimal([]byte(s), 3)
+		if err != nil || strings.Contains(s, "_") {
+			return
+		}
+
+		for _, q := range []string{s, " " + s + "\t", `"` + s + `"`} {
+			if w, err := fpdecimal.ParseFixedPointDecimalText([]byte(q), 3); err != nil || w != v {
+				t.Error(q, v, w, err)
+			}
+		}
+	})
+}
+
+func TestParseFixedPointDecimalWide(t *testing.T) {
+	tests := []struct {
+		s  string
+		p  uint8
+		hi int64
+		lo uint64
+	}{
+		{"+1.5", 18, 0, 1_500_000_000_000_000_000},
+		{"-0.0000000000000000019", 18, -1, math.MaxUint64},
+		{"00000000000000000000000000000000000000000001", 0, 0, 1},
+		{"1.", 3, 0, 1000},
+		{"18446744073709551616", 0, 1, 0},
+	}
+	for _, tc := range tests {
+		t.Run(tc.s, func(t *testing.T) {
+			hi, lo, err := fpdecimal.ParseFixedPointDecimalWide([]byte(tc.s), tc.p)
+			if err != nil || hi != tc.hi || lo != tc.lo {
+				t.Error(hi, lo, err)
+			}
+		})
+	}
+}
+
+func TestParseFixedPointDecimalWide_Error(t *testing.T) {
+	tests := []struct {
+		s   string
+		err string
+	}{
+		{"", "empty string"},
+		{"-", "missing digits after sign"},
+		{"1.2.3", "multiple dots"},
+		{"1a", "bad digit"},
+		{"170141183460469231731.687303715884105728", "overflow"},
+		{"-170141183460469231731.687303715884105729", "overflow"},
+		{"340282366920938463463.374607431768211456", "overflow"},
+		{"1000000000000000000000000000000000000000", "overflow"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.s, func(t *testing.T) {
+			hi, lo, err := fpdecimal.ParseFixedPointDecimalWide([]byte(tc.s), 18)
+			if err == nil || err.Error() != tc.err {
+				t.Error(err)
+			}
+			if hi != 0 || lo != 0 {
+				t.Error(hi, lo)
+			}
+		})
+	}
+}
+
+func TestParseFixedPointDecimalWideText(t *testing.T) {
+	hi, lo, err := fpdecimal.ParseFixedPointDecimalWideText([]byte(` "100_000_000_000_000_000_000.5" `), 1)
+	if err != nil || wideBig(hi, lo).String() != "1000000000000000000005" {
+		t.Error(hi, lo, err)
+	}
+
+	if _, _, err := fpdecimal.ParseFixedPointDecimalWideText([]byte("1__0"), 1); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func FuzzParseFixedPointDecimalWide(f *testing.F) {
+	for _, tc := range testsFloats {
+		for _, s := range tc.vals {
+			f.Add(s)
+			f.Add("-" + s)
+		}
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		hi, lo, err := fpdecimal.ParseFixedPointDecimalWide([]byte(s), 3)
+		if err != nil {
+			if hi != 0 || lo != 0 {
+				t.Error("has to be 0 on error")
+			}
+			return
+		}
+
+		if v, err := fpdecimal.ParseFixedPointDecimal([]byte(s), 3); err == nil && hi == int64(lo)>>63 && v != int64(lo) {
+			t.Error(s, v, hi, lo)
+		}
+	})
+}
+
+func BenchmarkParseFixedPointDecimalWide(b *testing.B) {
+	for _, tc := range testsFloats {
+		vals := make([][]byte, 0, len(tc.vals))
+		for _, s := range tc.vals {
+			vals = append(vals, []byte(s))
+		}
+
+		b.Run(tc.name, func(b *testing.B) {
+			for n := 0; n < b.N; n++ {
+				if _, _, err := fpdecimal.ParseFixedPointDecimalWide(vals[n%len(vals)], 18); err != nil {
+					b.Error(err)
+				}
+			}
+		})
+	}
+}
+
+func TestParseFixedPointDecimalExp(t *testing.T) {
+	tests := []struct {
+		s string
+		v int64
+	}{
+		{"0", 0},
+		{"-0", 0},
+		{"+1", 1000},
+		{"1.5", 1500},
+		{".5", 500},
+		{"5.", 5000},
+		{"-1.500000000000", -1500},
+		{"0.001", 1},
+		{"1.5e3", 1_500_000},
+		{"15E-1", 1500},
+		{"1000e-6", 1},
+		{"0e1000000000", 0},
+		{"0e-1000000000000", 0},
+		{"000123.4560", 123456},
+		{"1.000000000000000000000000000000", 1000},
+		{"9223372036854775.807", math.MaxInt64},
+		{"-9223372036854775.808", math.MinInt64},
+	}
+	for _, tc := range tests {
+		t.Run(tc.s, func(t *testing.T) {
+			if v, err := fpdecimal.ParseFixedPointDecimalExp([]byte(tc.s), 3); err != nil || v != tc.v {
+				t.Error(v, tc.v, err)
+			}
+		})
+	}
+}
+
+func TestParseFixedPointDecimalExp_Error(t *testing.T) {
+	tests := []struct {
+		s   string
+		err string
+	}{
+		{"", "empty string"},
+		{"-", "missing digits"},
+		{".", "missing digits"},
+		{"e5", "missing digits"},
+		{"1e", "missing digits in exponent"},
+		{"1e+", "missing digits in exponent"},
+		{"1e1.5", "bad digit"},
+		{"1.2.3", "multiple dots"},
+		{"1,5", "bad digit"},
+		{" 1", "bad digit"},
+		{"NaN", "bad digit"},
+		{"0.0001", "more fraction digits than supported"},
+		{"1e-4", "more fraction digits than supported"},
+		{"1e-1000000000000", "more fraction digits than supported"},
+		{"9223372036854775.808", "overflow"},
+		{"-9223372036854775.809", "overflow"},
+		{"1e16", "overflow"},
+		{"123456789012345678901234567890", "overflow"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.s, func(t *testing.T) {
+			v, err := fpdecimal.ParseFixedPointDecimalExp([]byte(tc.s), 3)
+			if err == nil || err.Error() != tc.err {
+				t.Error(err)
+			}
+			if v != 0 {
+				t.Error(v)
+			}
+		})
+	}
+}
+
+func FuzzParseFixedPointDecimalExp(f *testing.F) {
+	for _, tc := range testsFloats {
+		for _, s := range tc.vals {
+			f.Add(s)
+			f.Add("-" + s + "e2")
+		}
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		v, err := fpdecimal.ParseFixedPointDecimalExp([]byte(s), 3)
+		if err != nil {
+			if v != 0 {
+				t.Error("has to be 0 on error")
+			}
+			return
+		}
+
+		// lossless, so printed value parses back to same value
+		if q, err := fpdecimal.ParseFixedPointDecimalExp([]byte(fpdecimal.FixedPointDecimalToString(v, 3)), 3); err != nil || q != v {
+			t.Error(s, v, q, err)
+		}
+		if q, err := fpdecimal.ParseFixedPointDecimal([]byte(s), 3); err == nil && !strings.ContainsAny(s, "eE") && q != v {
+			t.Error(s, v, q)
+		}
+	})
+}
+
+func TestParseFixedPointDecimalUint(t *testing.T) {
+	tests := []struct {
+		s string
+		v uint64
+	}{
+		{"0", 0},
+		{"+1.5", 1500},
+		{"0.0019", 1},
+		{"9223372036854775.808", math.MaxInt64 + 1},
+		{"18446744073709551.615", math.MaxUint64},
+		{"18446744073709551.6159", math.MaxUint64},
+	}
+	for _, tc := range tests {
+		t.Run(tc.s, func(t *testing.T) {
+			v, err := fpdecimal.ParseFixedPointDecimalUint([]byte(tc.s), 3)
+			if err != nil || v != tc.v {
+				t.Error(v, err)
+			}
+		})
+	}
+}
+
+func TestParseFixedPointDecimalUint_Error(t *testing.T) {
+	tests := []struct {
+		s   string
+		err string
+	}{
+		{"", "empty string"},
+		{"+", "missing digits after sign"},
+		{"-1", "negative value"},
+		{"-0", "negative value"},
+		{"1.2.3", "multiple dots"},
+		{"1a", "bad digit"},
+		{"18446744073709551.616", "overflow"},
+		{"18446744073709552", "overflow"},
+		{"100000000000000000000", "overflow"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.s, func(t *testing.T) {
+			v, err := fpdecimal.ParseFixedPointDecimalUint([]byte(tc.s), 3)
+			if err == nil || err.Error() != tc.err {
+				t.Error(err)
+			}
+			if v != 0 {
+				t.Error(v)
+			}
+		})
+	}
+}
+
+func TestParseFixedPointDecimalUintText(t *testing.T) {
+	if v, err := fpdecimal.ParseFixedPointDecimalUintText([]byte(` "18_446_744_073_709_551.615" `), 3); err != nil || v != math.MaxUint64 {
+		t.Error(v, err)
+	}
+
+	if _, err := fpdecimal.ParseFixedPointDecimalUintText([]byte("1__0"), 3); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func FuzzParseFixedPointDecimalUint(f *testing.F) {
+	for _, tc := range testsFloats {
+		for _, s := range tc.vals {
+			f.Add(s)
+			f.Add("+" + s)
+		}
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		u, err := fpdecimal.ParseFixedPointDecimalUint([]byte(s), 3)
+		if err != nil {
+			if u != 0 {
+				t.Error("has to be 0 on error")
+			}
+			return
+		}
+
+		if v, err := fpdecimal.ParseFixedPointDecimal([]byte(s), 3); err == nil && u <= math.MaxInt64 && v != int64(u) {
+			t.Error(s, v, u)
+		}
+	})
+}
@@ -0,0 +1,197 @@
+package fpdecimal
+
+import (
+	"bytes"
+	"math/bits"
+)
+
+// percentScale is scaled value of ratio 1, which is 100% and 10000bp.
+const percentScale = 100_000_000
+
+const (
+	percentDigits     = 6
+	basisPointsDigits = 4
+)
+
+var errMissingUnit = &errorString{"missing unit"}
+
+// Percent is ratio in percents with 6 fractional digits, such as 2.5%.
+// It has same scaled value as BasisPoints, so conversion between them is exact.
+type Percent struct{ v int64 }
+
+// BasisPoints is ratio in hundredths of percent with 4 fractional digits, such as 25bp.
+type BasisPoints struct{ v int64 }
+
+func PercentFromInt(v int64) Percent { return Percent{v * percentScale / 100} }
+
+func BasisPointsFromInt(v int64) BasisPoints { return BasisPoints{v * percentScale / 10_000} }
+
+// PercentFromString parses percents, unit is required: 2.5%.
+func PercentFromString(s string) (Percent, error) {
+	var p Percent
+	err := p.UnmarshalText([]byte(s))
+	return p, err
+}
+
+// BasisPointsFromString parses basis points, unit is required: 25bp.
+func BasisPointsFromString(s string) (BasisPoints, error) {
+	var p BasisPoints
+	err := p.UnmarshalText([]byte(s))
+	return p, err
+}
+
+func (p Percent) BasisPoints() BasisPoints { return BasisPoints(p) }
+
+func (p BasisPoints) Percent() Percent { return Percent(p) }
+
+// Scaled returns ratio scaled by 10^8, which is millionths of percent and ten thousandths of basis point.
+func (p Percent) Scaled() int64 { return p.v }
+
+// Scaled returns same value as Percent.Scaled.
+func (p BasisPoints) Scaled() int64 { return p.v }
+
+func (p Percent) String() string { return FixedPointDecimalToString(p.v, percentDigits) + "%" }
+
+func (p BasisPoints) String() string { return FixedPointDecimalToString(p.v, basisPointsDigits) + "bp" }
+
+func (p Percent) MarshalText() ([]byte, error) { return []byte(p.String()), nil }
+
+func (p BasisPoints) MarshalText() ([]byte, error) { return []byte(p.String()), nil }
+
+// UnmarshalText decodes text with unit, surrounding whitespace and quotes are ignored.
+func (p *Percent) UnmarshalText(b []byte) (err error) {
+	p.v, err = parseRatio(b, "%", percentDigits)
+	return err
+}
+
+func (p *BasisPoints) UnmarshalText(b []byte) (err error) {
+	p.v, err = parseRatio(b, "bp", basisPointsDigits)
+	return err
+}
+
+func parseRatio(b []byte, unit string, p uint8) (int64, error) {
+	var buf [64]byte
+	b, err := trimText(b, buf[:0])
+	if err != nil {
+		return 0, err
+	}
+	s, ok := bytes.CutSuffix(b, []byte(unit))
+	if !ok {
+		return 0, errMissingUnit
+	}
+	return ParseFixedPointDecimal(bytes.TrimSpace(s), p)
+}
+
+// Apply returns percent of fixed-point decimal v, rounded by mode.
+// Product is 128 bits, so it does not overflow unless result does not fit int64.
+func (p Percent) Apply(v int64, mode RoundingMode) (int64, error) {
+	return MulDivRound(v, p.v, percentScale, mode)
+}
+
+// ApplyUint is Apply for uint64, negative result is error.
+func (p Percent) ApplyUint(v uint64, mode RoundingMode) (uint64, error) {
+	q, err := mulDivRoundUint(v, abs(p.v), percentScale, p.v < 0, mode)
+	if err != nil {
+		return 0, err
+	}
+	return unsigned(q, p.v < 0)
+}
+
+// ApplyWide is Apply for two's complement of high and low bits.
+func (p Percent) ApplyWide(hi int64, lo uint64, mode RoundingMode) (int64, uint64, error) {
+	return MulDivRoundWide(hi, lo, p.v>>63, uint64(p.v), 0, percentScale, mode)
+}
+
+// PercentOf returns how many percents is v of total, rounded by mode.
+// Both are fixed-point decimals of same fractions.
+func PercentOf(v, total int64, mode RoundingMode) (Percent, error) {
+	q, err := MulDivRound(v, percentScale, total, mode)
+	return Percent{q}, err
+}
+
+// PercentOfUint is PercentOf for uint64.
+func PercentOfUint(v, total uint64, mode RoundingMode) (Percent, error) {
+	q, err := MulDivRoundUint(v, percentScale, total, mode)
+	if err != nil {
+		return Percent{}, err
+	}
+	s, err := signed(q, false)
+	return Percent{s}, err
+}
+
+// PercentChange returns change from one value to another relative to magnitude of first, rounded by mode.
+// Increase is positive, even when values are negative.
+// Both are fixed-point decimals of same fractions, difference of them does not overflow.
+func PercentChange(from, to int64, mode RoundingMode) (Percent, error) {
+	if from == 0 {
+		return Percent{}, errDivisionByZero
+	}
+
+	// magnitude of difference fits uint64
+	var d uint64
+	neg := to < from
+	if neg {
+		d = uint64(from) - uint64(to)
+	} else {
+		d = uint64(to) - uint64(from)
+	}
+
+	q, err := mulDivRound(d, percentScale, abs(from), neg, mode)
+	return Percent{q}, err
+}
+
+// PercentChangeUint is PercentChange for uint64.
+func PercentChangeUint(from, to uint64, mode RoundingMode) (Percent, error) {
+	if from == 0 {
+		return Percent{}, errDivisionByZero
+	}
+
+	d, neg := to-from, to < from
+	if neg {
+		d = from - to
+	}
+
+	q, err := mulDivRound(d, percentScale, from, neg, mode)
+	return Percent{q}, err
+}
+
+// PercentOfWide is PercentOf for two's complement of high and low bits.
+func PercentOfWide(hi int64, lo uint64, thi int64, tlo uint64, mode RoundingMode) (Percent, error) {
+	qhi, q, err := MulDivRoundWide(hi, lo, 0, percentScale, thi, tlo, mode)
+	if err != nil {
+		return Percent{}, err
+	}
+	if qhi != int64(q)>>63 {
+		return Percent{}, errOverflow
+	}
+	return Percent{int64(q)}, nil
+}
+
+// PercentChangeWide is PercentChange for two's complement of high and low bits.
+func PercentChangeWide(fhi int64, flo uint64, thi int64, tlo uint64, mode RoundingMode) (Percent, error) {
+	if fhi == 0 && flo == 0 {
+		return Percent{}, errDivisionByZero
+	}
+
+	// magnitude of difference fits 128 bits
+	var dhi, dlo, b uint64
+	neg := thi < fhi || (thi == fhi && tlo < flo)
+	if neg {
+		dlo, b = bits.Sub64(flo, tlo, 0)
+		dhi, _ = bits.Sub64(uint64(fhi), uint64(thi), b)
+	} else {
+		dlo, b = bits.Sub64(tlo, flo, 0)
+		dhi, _ = bits.Sub64(uint64(thi), uint64(fhi), b)
+	}
+
+	uhi, ulo, _ := absWide(fhi, flo)
+	qhi, q, err := mulDivRoundWide(dhi, dlo, 0, percentScale, uhi, ulo, neg, mode)
+	if err != nil {
+		return Percent{}, err
+	}
+	if qhi != 0 {
+		return Percent{}, errOverflow
+	}
+	v, err := signed(q, neg)
+	return Percent{v}, err
+}
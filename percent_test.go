@@ -0,0 +1,281 @@
+package fpdecimal_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/nikolaydubina/fpdecimal"
+)
+
+func TestPercent(t *testing.T) {
+	tests := []struct {
+		s  string
+		bp string
+		v  int64
+	}{
+		{"0%", "0bp", 0},
+		{"2.5%", "250bp", 2_500_000},
+		{"-0.25%", "-25bp", -250_000},
+		{"100%", "10000bp", 100_000_000},
+		{"0.000001%", "0.0001bp", 1},
+	}
+	for _, tc := range tests {
+		t.Run(tc.s, func(t *testing.T) {
+			p, err := fpdecimal.PercentFromString(tc.s)
+			if err != nil || p.Scaled() != tc.v {
+				t.Error(p, err)
+			}
+			if s := p.String(); s != tc.s {
+				t.Error(s)
+			}
+
+			bp, err := fpdecimal.BasisPointsFromString(tc.bp)
+			if err != nil || bp != p.BasisPoints() || bp.Percent() != p || bp.Scaled() != tc.v {
+				t.Error(bp, err)
+			}
+			if s := bp.String(); s != tc.bp {
+				t.Error(s)
+			}
+		})
+	}
+
+	if p := fpdecimal.PercentFromInt(3); p.String() != "3%" || p != fpdecimal.BasisPointsFromInt(300).Percent() {
+		t.Error(p)
+	}
+}
+
+func TestPercent_Text(t *testing.T) {
+	var v struct {
+		Fee   fpdecimal.Percent     `json:"fee"`
+		Extra fpdecimal.BasisPoints `json:"extra"`
+	}
+	if err := json.Unmarshal([]byte(`{"fee": " 1_000.5 % ", "extra": "'25bp'"}`), &v); err != nil {
+		t.Fatal(err)
+	}
+	if v.Fee.Scaled() != 1_000_500_000 || v.Extra.Scaled() != 250_000 {
+		t.Error(v)
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil || string(b) != `{"fee":"1000.5%","extra":"25bp"}` {
+		t.Error(string(b), err)
+	}
+}
+
+func TestPercent_Error(t *testing.T) {
+	for _, s := range []string{"", "%", "2.5", "0.025", "2.5bp", "25%bp", "x%", "1__0%"} {
+		t.Run(s, func(t *testing.T) {
+			if v, err := fpdecimal.PercentFromString(s); err == nil || v.Scaled() != 0 {
+				t.Error(v, err)
+			}
+		})
+	}
+	for _, s := range []string{"", "bp", "25", "25%", "2x5bp"} {
+		t.Run(s, func(t *testing.T) {
+			if v, err := fpdecimal.BasisPointsFromString(s); err == nil || v.Scaled() != 0 {
+				t.Error(v, err)
+			}
+		})
+	}
+}
+
+func TestPercent_Apply(t *testing.T) {
+	tests := []struct {
+		v    int64
+		p    string
+		mode fpdecimal.RoundingMode
+		q    int64
+	}{
+		{100_000, "2.5%", fpdecimal.ToNearestEven, 2_500},
+		{1_999, "2.5%", fpdecimal.ToNearestEven, 50},
+		{1_999, "2.5%", fpdecimal.ToZero, 49},
+		{-1_999, "2.5%", fpdecimal.ToNegativeInf, -50},
+		{1_000, "-0.01%", fpdecimal.ToNearestEven, 0},
+		{1_000, "-0.05%", fpdecimal.ToNearestAway, -1},
+		{math.MaxInt64, "100%", fpdecimal.ToNearestEven, math.MaxInt64},
+		{math.MaxInt64, "50%", fpdecimal.ToZero, math.MaxInt64 / 2},
+	}
+	for _, tc := range tests {
+		t.Run(fmt.Sprint(tc.v, tc.p, tc.mode), func(t *testing.T) {
+			p, _ := fpdecimal.PercentFromString(tc.p)
+			if v, err := p.Apply(tc.v, tc.mode); err != nil || v != tc.q {
+				t.Error(v, tc.q, err)
+			}
+		})
+	}
+
+	if v, err := fpdecimal.PercentFromInt(200).Apply(math.MaxInt64, fpdecimal.ToZero); err == nil || v != 0 {
+		t.Error(v, err)
+	}
+}
+
+func TestPercentOf(t *testing.T) {
+	tests := []struct {
+		v, total int64
+		mode     fpdecimal.RoundingMode
+		p        string
+	}{
+		{25, 1_000, fpdecimal.ToNearestEven, "2.5%"},
+		{1, 3, fpdecimal.ToNearestEven, "33.333333%"},
+		{2, 3, fpdecimal.ToNearestEven, "66.666667%"},
+		{2, 3, fpdecimal.ToZero, "66.666666%"},
+		{-1, 3, fpdecimal.ToNearestEven, "-33.333333%"},
+		{math.MaxInt64, math.MaxInt64, fpdecimal.ToNearestEven, "100%"},
+		{math.MinInt64, math.MaxInt64, fpdecimal.ToNearestEven, "-100%"},
+	}
+	for _, tc := range tests {
+		t.Run(fmt.Sprint(tc.v, tc.total, tc.mode), func(t *testing.T) {
+			if p, err := fpdecimal.PercentOf(tc.v, tc.total, tc.mode); err != nil || p.String() != tc.p {
+				t.Error(p, tc.p, err)
+			}
+		})
+	}
+
+	if p, err := fpdecimal.PercentOf(1, 0, fpdecimal.ToZero); err == nil || p.Scaled() != 0 {
+		t.Error(p, err)
+	}
+	if p, err := fpdecimal.PercentOf(math.MaxInt64, 1, fpdecimal.ToZero); err == nil || p.Scaled() != 0 {
+		t.Error(p, err)
+	}
+}
+
+func TestPercentChange(t *testing.T) {
+	tests := []struct {
+		from, to int64
+		mode     fpdecimal.RoundingMode
+		p        string
+	}{
+		{100, 125, fpdecimal.ToNearestEven, "25%"},
+		{125, 100, fpdecimal.ToNearestEven, "-20%"},
+		{100, 100, fpdecimal.ToNearestEven, "0%"},
+		{3, 4, fpdecimal.ToNearestEven, "33.333333%"},
+		{3, 2, fpdecimal.AwayFromZero, "-33.333334%"},
+		{-100, -50, fpdecimal.ToNearestEven, "50%"},
+		{-100, 50, fpdecimal.ToNearestEven, "150%"},
+		{math.MaxInt64, math.MinInt64 + 1, fpdecimal.ToNearestEven, "-200%"},
+		{math.MinInt64, math.MaxInt64, fpdecimal.ToZero, "199.999999%"},
+	}
+	for _, tc := range tests {
+		t.Run(fmt.Sprint(tc.from, tc.to, tc.mode), func(t *testing.T) {
+			if p, err := fpdecimal.PercentChange(tc.from, tc.to, tc.mode); err != nil || p.String() != tc.p {
+				t.Error(p, tc.p, err)
+			}
+		})
+	}
+
+	if p, err := fpdecimal.PercentChange(0, 1, fpdecimal.ToZero); err == nil || p.Scaled() != 0 {
+		t.Error(p, err)
+	}
+	if p, err := fpdecimal.PercentChange(1, math.MaxInt64, fpdecimal.ToZero); err == nil || p.Scaled() != 0 {
+		t.Error(p, err)
+	}
+}
+
+func TestPercent_Uint(t *testing.T) {
+	p, _ := fpdecimal.PercentFromString("2.5%")
+	if v, err := p.ApplyUint(math.MaxUint64, fpdecimal.ToZero); err != nil || v != math.MaxUint64/40 {
+		t.Error(v, err)
+	}
+
+	n, _ := fpdecimal.PercentFromString("-0.05%")
+	if v, err := n.ApplyUint(999, fpdecimal.ToZero); err != nil || v != 0 {
+		t.Error(v, err)
+	}
+	if v, err := n.ApplyUint(1_000, fpdecimal.ToNearestAway); err == nil || v != 0 {
+		t.Error(v, err)
+	}
+
+	if p, err := fpdecimal.PercentOfUint(math.MaxUint64, math.MaxUint64, fpdecimal.ToZero); err != nil || p.String() != "100%" {
+		t.Error(p, err)
+	}
+	if p, err := fpdecimal.PercentOfUint(math.MaxUint64, 1, fpdecimal.ToZero); err == nil || p.Scaled() != 0 {
+		t.Error(p, err)
+	}
+	if p, err := fpdecimal.PercentOfUint(1, 0, fpdecimal.ToZero); err == nil || p.Scaled() != 0 {
+		t.Error(p, err)
+	}
+
+	if p, err := fpdecimal.PercentChangeUint(math.MaxUint64, 0, fpdecimal.ToZero); err != nil || p.String() != "-100%" {
+		t.Error(p, err)
+	}
+	if p, err := fpdecimal.PercentChangeUint(3, 2, fpdecimal.AwayFromZero); err != nil || p.String() != "-33.333334%" {
+		t.Error(p, err)
+	}
+	if p, err := fpdecimal.PercentChangeUint(0, 1, fpdecimal.ToZero); err == nil || p.Scaled() != 0 {
+		t.Error(p, err)
+	}
+}
+
+func TestPercent_Wide(t *testing.T) {
+	p, _ := fpdecimal.PercentFromString("-2.5%")
+	if hi, lo, err := p.ApplyWide(math.MaxInt64, math.MaxUint64, fpdecimal.ToZero); err != nil || wideBig(hi, lo).Cmp(new(big.Int).Quo(wideBig(math.MaxInt64, math.MaxUint64), big.NewInt(-40))) != 0 {
+		t.Error(hi, lo, err)
+	}
+	if hi, lo, err := p.ApplyWide(0, 1_000, fpdecimal.ToNearestEven); err != nil || hi != -1 || lo != math.MaxUint64-24 {
+		t.Error(hi, lo, err)
+	}
+
+	double, _ := fpdecimal.PercentFromString("200%")
+	if hi, lo, err := double.ApplyWide(math.MaxInt64, math.MaxUint64, fpdecimal.ToZero); err == nil || hi != 0 || lo != 0 {
+		t.Error(hi, lo, err)
+	}
+
+	if p, err := fpdecimal.PercentOfWide(math.MaxInt64, math.MaxUint64, math.MaxInt64, math.MaxUint64, fpdecimal.ToZero); err != nil || p.String() != "100%" {
+		t.Error(p, err)
+	}
+	if p, err := fpdecimal.PercentOfWide(0, 1, math.MaxInt64, math.MaxUint64, fpdecimal.ToPositiveInf); err != nil || p.String() != "0.000001%" {
+		t.Error(p, err)
+	}
+	if p, err := fpdecimal.PercentOfWide(math.MaxInt64, math.MaxUint64, 0, 1, fpdecimal.ToZero); err == nil || p.Scaled() != 0 {
+		t.Error(p, err)
+	}
+	if p, err := fpdecimal.PercentOfWide(0, 1, 0, 0, fpdecimal.ToZero); err == nil || p.Scaled() != 0 {
+		t.Error(p, err)
+	}
+
+	if p, err := fpdecimal.PercentChangeWide(math.MinInt64, 0, math.MaxInt64, math.MaxUint64, fpdecimal.ToZero); err != nil || p.String() != "199.999999%" {
+		t.Error(p, err)
+	}
+	if p, err := fpdecimal.PercentChangeWide(0, 3, 0, 2, fpdecimal.AwayFromZero); err != nil || p.String() != "-33.333334%" {
+		t.Error(p, err)
+	}
+	if p, err := fpdecimal.PercentChangeWide(0, 0, 0, 1, fpdecimal.ToZero); err == nil || p.Scaled() != 0 {
+		t.Error(p, err)
+	}
+}
+
+func FuzzPercentChange(f *testing.F) {
+	tests := [][2]int64{
+		{100, 125},
+		{3, 2},
+		{-100, 50},
+		{math.MinInt64, math.MaxInt64},
+	}
+	for _, tc := range tests {
+		f.Add(tc[0], tc[1])
+		f.Add(tc[1], tc[0])
+	}
+	f.Fuzz(func(t *testing.T, from, to int64) {
+		if from == 0 {
+			t.Skip()
+		}
+
+		// (to - from) / |from| scaled by 10^8, truncated
+		d := new(big.Int).Sub(big.NewInt(to), big.NewInt(from))
+		d.Mul(d, big.NewInt(100_000_000))
+		d.Quo(d, new(big.Int).Abs(big.NewInt(from)))
+
+		p, err := fpdecimal.PercentChange(from, to, fpdecimal.ToZero)
+		if d.IsInt64() != (err == nil) || (err == nil && p.Scaled() != d.Int64()) {
+			t.Error(from, to, p, d, err)
+		}
+
+		w, err := fpdecimal.PercentChangeWide(from>>63, uint64(from), to>>63, uint64(to), fpdecimal.ToZero)
+		if d.IsInt64() != (err == nil) || w != p {
+			t.Error(from, to, w, d, err)
+		}
+	})
+}
@@ -0,0 +1,187 @@
+package fpdecimal
+
+import "math/bits"
+
+// PostgreSQL NUMERIC binary format, src/backend/utils/adt/numeric.c
+const (
+	pgNumericPos    = 0x0000
+	pgNumericNeg    = 0x4000
+	pgNumericNaN    = 0xc000
+	pgNumericPInf   = 0xd000
+	pgNumericNInf   = 0xf000
+	pgNumericBase   = 10_000
+	pgNumericHeader = 8
+)
+
+var (
+	errPostgresNumericNaN    = &errorString{"postgres numeric: NaN"}
+	errPostgresNumericInf    = &errorString{"postgres numeric: infinity"}
+	errPostgresNumericLength = &errorString{"postgres numeric: bad length"}
+	errPostgresNumericSign   = &errorString{"postgres numeric: bad sign"}
+	errPostgresNumericDigit  = &errorString{"postgres numeric: bad digit"}
+)
+
+// AppendPostgresNumeric appends fixed-point decimal of p fractions in PostgreSQL NUMERIC binary format.
+// This is what PostgreSQL numeric_send produces: ndigits, weight, sign, dscale, and base-10000 digits, all big-endian.
+// Display scale is p.
+func AppendPostgresNumeric(b []byte, v int64, p uint8) []byte {
+	return appendPostgresNumeric(b, 0, abs(v), v < 0, p)
+}
+
+// AppendPostgresNumericUint is AppendPostgresNumeric for uint64.
+func AppendPostgresNumericUint(b []byte, v uint64, p uint8) []byte {
+	return appendPostgresNumeric(b, 0, v, false, p)
+}
+
+// AppendPostgresNumericWide is AppendPostgresNumeric for two's complement of high and low bits.
+func AppendPostgresNumericWide(b []byte, hi int64, lo uint64, p uint8) []byte {
+	uhi, ulo, neg := absWide(hi, lo)
+	return appendPostgresNumeric(b, uhi, ulo, neg, p)
+}
+
+// appendPostgresNumeric appends value of 128-bit magnitude hi:lo and sign neg.
+func appendPostgresNumeric(b []byte, hi, lo uint64, neg bool, p uint8) []byte {
+	sign := uint16(pgNumericPos)
+	if neg {
+		sign = pgNumericNeg
+	}
+
+	// 128 bits have at most 39 digits, whole takes at most 10 base-10000 digits and fraction at most 5
+	var digits [15]uint16
+	var n int
+
+	// whole
+	whi, wlo, frac := quoRemWide(hi, lo, uint64(pow10[p]))
+	var w [10]uint16
+	nw := 0
+	for whi != 0 || wlo != 0 {
+		var r uint64
+		whi, wlo, r = quoRemWide(whi, wlo, pgNumericBase)
+		w[nw] = uint16(r)
+		nw++
+	}
+	for i := nw - 1; i >= 0; i-- {
+		digits[n] = w[i]
+		n++
+	}
+	weight := nw - 1
+
+	// fraction, aligned to base-10000 digits
+	nf := (int(p) + 3) / 4
+	if nf > 0 {
+		r := 4*nf - int(p)
+		f := digits[n : n+nf]
+		f[nf-1] = uint16(frac%uint64(pow10[4-r])) * uint16(pow10[r])
+		frac /= uint64(pow10[4-r])
+		for i := nf - 2; i >= 0; i-- {
+			f[i] = uint16(frac % pgNumericBase)
+			frac /= pgNumericBase
+		}
+		n += nf
+	}
+
+	// strip zeros
+	d := digits[:n]
+	for len(d) > 0 && d[0] == 0 {
+		d = d[1:]
+		weight--
+	}
+	for len(d) > 0 && d[len(d)-1] == 0 {
+		d = d[:len(d)-1]
+	}
+	if len(d) == 0 {
+		weight = 0
+	}
+
+	b = append(b,
+		byte(len(d)>>8), byte(len(d)),
+		byte(uint16(weight)>>8), byte(weight),
+		byte(sign>>8), byte(sign),
+		0, p,
+	)
+	for _, q := range d {
+		b = append(b, byte(q>>8), byte(q))
+	}
+	return b
+}
+
+// ParsePostgresNumeric parses PostgreSQL NUMERIC binary format into fixed-point decimal of p fractions.
+// Returns error for NaN, infinity, values that do not fit int64, or have more than p fractions.
+func ParsePostgresNumeric(b []byte, p uint8) (int64, error) {
+	hi, u, neg, err := parsePostgresNumeric(b, p)
+	if err != nil {
+		return 0, err
+	}
+	if hi != 0 {
+		return 0, errOverflow
+	}
+	return signed(u, neg)
+}
+
+// ParsePostgresNumericUint is ParsePostgresNumeric for uint64, negative value is error.
+func ParsePostgresNumericUint(b []byte, p uint8) (uint64, error) {
+	hi, u, neg, err := parsePostgresNumeric(b, p)
+	if err != nil {
+		return 0, err
+	}
+	if hi != 0 {
+		return 0, errOverflow
+	}
+	return unsigned(u, neg)
+}
+
+// ParsePostgresNumericWide is ParsePostgresNumeric for two's complement of high and low bits.
+func ParsePostgresNumericWide(b []byte, p uint8) (hi int64, lo uint64, err error) {
+	uhi, ulo, neg, err := parsePostgresNumeric(b, p)
+	if err != nil {
+		return 0, 0, err
+	}
+	return signedWide(uhi, ulo, neg)
+}
+
+// parsePostgresNumeric returns 128-bit magnitude and sign of value.
+func parsePostgresNumeric(b []byte, p uint8) (hi, lo uint64, neg bool, err error) {
+	if len(b) < pgNumericHeader {
+		return 0, 0, false, errPostgresNumericLength
+	}
+
+	ndigits := int(uint16(b[0])<<8 | uint16(b[1]))
+	weight := int(int16(uint16(b[2])<<8 | uint16(b[3])))
+	sign := uint16(b[4])<<8 | uint16(b[5])
+
+	switch sign {
+	case pgNumericPos, pgNumericNeg:
+	case pgNumericNaN:
+		return 0, 0, false, errPostgresNumericNaN
+	case pgNumericPInf, pgNumericNInf:
+		return 0, 0, false, errPostgresNumericInf
+	default:
+		return 0, 0, false, errPostgresNumericSign
+	}
+
+	if len(b) != pgNumericHeader+2*ndigits {
+		return 0, 0, false, errPostgresNumericLength
+	}
+
+	for i := 0; i < ndigits; i++ {
+		d := uint64(uint16(b[pgNumericHeader+2*i])<<8 | uint16(b[pgNumericHeader+2*i+1]))
+		if d >= pgNumericBase {
+			return 0, 0, false, errPostgresNumericDigit
+		}
+
+		// digit is d×10000^(weight-i), scaled by 10^p
+		dhi, dlo, err := scaleExpWide(0, d, 4*(weight-i), p)
+		if err != nil {
+			return 0, 0, false, err
+		}
+
+		var c uint64
+		lo, c = bits.Add64(lo, dlo, 0)
+		hi, c = bits.Add64(hi, dhi, c)
+		if c != 0 {
+			return 0, 0, false, errOverflow
+		}
+	}
+
+	return hi, lo, sign == pgNumericNeg, nil
+}
@@ -0,0 +1,264 @@
+package fpdecimal_test
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/nikolaydubina/fpdecimal"
+)
+
+func TestAppendPostgresNumeric(t *testing.T) {
+	tests := []struct {
+		v int64
+		p uint8
+		b string
+	}{
+		{0, 3, "0000000000000003"},
+		{1000, 3, "00010000000000030001"},
+		{-12500, 3, "0002000040000003000c1388"},
+		{1, 3, "0001ffff00000003000a"},
+		{12345678, 3, "0003000100000003000109291a7c"},
+		{10000000, 3, "00010001000000030001"},
+		{120, 6, "0002ffff00000006000107d0"},
+		{1000000000001, 4, "00040002000000040001000000000001"},
+		{123, 0, "0001000000000000007b"},
+		{math.MaxInt64, 3, "000500030000000324070e880e6512a71f86"},
+		{math.MinInt64, 6, "0006000340000006000908b91c231ac61e4e0320"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.b, func(t *testing.T) {
+			b := fpdecimal.AppendPostgresNumeric(nil, tc.v, tc.p)
+			if s := hex.EncodeToString(b); s != tc.b {
+				t.Error(s, tc.b)
+			}
+
+			v, err := fpdecimal.ParsePostgresNumeric(b, tc.p)
+			if err != nil {
+				t.Error(err)
+			}
+			if v != tc.v {
+				t.Error(v, tc.v)
+			}
+		})
+	}
+}
+
+func TestPostgresNumericUint(t *testing.T) {
+	tests := []struct {
+		v uint64
+		p uint8
+		b string
+	}{
+		{0, 3, "0000000000000003"},
+		{12500, 3, "0002000000000003000c1388"},
+		{10_000_000_000_000_000_000, 0, "0001000400000000" + "03e8"},
+		{math.MaxUint64, 0, "00050004000000000734" + "1a5802e103bb064f"},
+		{math.MaxUint64, 3, "00060004000000030001" + "20fe1d101cca254f1806"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.b, func(t *testing.T) {
+			b := fpdecimal.AppendPostgresNumericUint(nil, tc.v, tc.p)
+			if s := hex.EncodeToString(b); s != tc.b {
+				t.Error(s, tc.b)
+			}
+			if v, err := fpdecimal.ParsePostgresNumericUint(b, tc.p); err != nil || v != tc.v {
+				t.Error(v, tc.v, err)
+			}
+		})
+	}
+
+	errs := []struct {
+		b   string
+		err string
+	}{
+		{"0001000040000000" + "0001", "negative value"},
+		{"0001000500000000" + "0001", "overflow"},
+		{"00050004000000000734" + "1a5802e103bb0650", "overflow"},
+		{"0000000000000000c000", "postgres numeric: bad length"},
+	}
+	for _, tc := range errs {
+		t.Run(tc.b, func(t *testing.T) {
+			b, _ := hex.DecodeString(tc.b)
+			if v, err := fpdecimal.ParsePostgresNumericUint(b, 0); err == nil || err.Error() != tc.err || v != 0 {
+				t.Error(v, err)
+			}
+		})
+	}
+
+	// negative zero is zero
+	if v, err := fpdecimal.ParsePostgresNumericUint([]byte{0, 0, 0, 0, 0x40, 0, 0, 3}, 3); err != nil || v != 0 {
+		t.Error(v, err)
+	}
+}
+
+func TestParsePostgresNumeric(t *testing.T) {
+	tests := []struct {
+		name string
+		b    string
+		p    uint8
+		v    int64
+	}{
+		{"zero with negative weight", "0000ffff00000000", 3, 0},
+		{"negative zero", "0000000040000000", 3, 0},
+		{"zero display scale", "00010000000000000001", 3, 1000},
+		{"display scale larger than fractions", "0002ffff00000008000107d0", 5, 12},
+		{"trailing zero digit", "000200000000000400010000", 3, 1000},
+		{"large weight", "00010004000000000001", 0, 10_000_000_000_000_000},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			b, _ := hex.DecodeString(tc.b)
+			v, err := fpdecimal.ParsePostgresNumeric(b, tc.p)
+			if err != nil {
+				t.Error(err)
+			}
+			if v != tc.v {
+				t.Error(v, tc.v)
+			}
+		})
+	}
+}
+
+func TestParsePostgresNumeric_Error(t *testing.T) {
+	tests := []struct {
+		name string
+		b    string
+		err  string
+	}{
+		{"empty", "", "postgres numeric: bad length"},
+		{"short header", "00010000000000", "postgres numeric: bad length"},
+		{"missing digits", "00020000000000000001", "postgres numeric: bad length"},
+		{"trailing data", "0001000000000000000100", "postgres numeric: bad length"},
+		{"NaN", "00000000c0000000", "postgres numeric: NaN"},
+		{"Infinity", "00000000d0000000", "postgres numeric: infinity"},
+		{"-Infinity", "00000000f0000000", "postgres numeric: infinity"},
+		{"bad sign", "0000000010000000", "postgres numeric: bad sign"},
+		{"bad digit", "00010000000000002710", "postgres numeric: bad digit"},
+		{"0.0001", "0001ffff000000040001", "more fraction digits than supported"},
+		{"1E-8", "0001fffe000000080001", "more fraction digits than supported"},
+		{"1E+16", "00010004000000000001", "overflow"},
+		{"1E+100", "00010019000000000001", "overflow"},
+		{"9223372036854775.808", "000500030000000324070e880e6512a71f90", "overflow"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			b, _ := hex.DecodeString(tc.b)
+			v, err := fpdecimal.ParsePostgresNumeric(b, 3)
+			if err == nil || err.Error() != tc.err {
+				t.Error(err)
+			}
+			if v != 0 {
+				t.Error(v)
+			}
+		})
+	}
+}
+
+func FuzzPostgresNumeric(f *testing.F) {
+	tests := []int64{0, 1, 1000, 12345678, math.MaxInt64}
+	for _, tc := range tests {
+		f.Add(tc, uint8(3))
+		f.Add(-tc, uint8(6))
+	}
+	f.Fuzz(func(t *testing.T, v int64, p uint8) {
+		if p > 18 {
+			t.Skip()
+		}
+		b := fpdecimal.AppendPostgresNumeric(nil, v, p)
+		if q, err := fpdecimal.ParsePostgresNumeric(b, p); err != nil || q != v {
+			t.Error(v, p, fmt.Sprintf("%x", b), q, err)
+		}
+	})
+}
+
+func FuzzParsePostgresNumeric(f *testing.F) {
+	tests := []string{
+		"0003000100000003000109291a7c",
+		"0006000340000006000908b91c231ac61e4e0320",
+		"0001fffe000000080001",
+		"00000000c0000000",
+	}
+	for _, tc := range tests {
+		b, _ := hex.DecodeString(tc)
+		f.Add(b)
+	}
+	f.Fuzz(func(t *testing.T, b []byte) {
+		v, err := fpdecimal.ParsePostgresNumeric(b, 3)
+		if err != nil {
+			if v != 0 {
+				t.Error("has to be 0 on error")
+			}
+			return
+		}
+		if q, err := fpdecimal.ParsePostgresNumeric(fpdecimal.AppendPostgresNumeric(nil, v, 3), 3); err != nil || q != v {
+			t.Error(v, q, err)
+		}
+	})
+}
+
+func BenchmarkAppendPostgresNumeric(b *testing.B) {
+	d := make([]byte, 0, 32)
+	for n := 0; n < b.N; n++ {
+		d = fpdecimal.AppendPostgresNumeric(d[:0], 12345678, 3)
+	}
+	if len(d) == 0 {
+		b.Error("empty")
+	}
+}
+
+func BenchmarkParsePostgresNumeric(b *testing.B) {
+	d := fpdecimal.AppendPostgresNumeric(nil, 12345678, 3)
+	for n := 0; n < b.N; n++ {
+		if v, err := fpdecimal.ParsePostgresNumeric(d, 3); err != nil || v != 12345678 {
+			b.Error(v, err)
+		}
+	}
+}
+
+func TestPostgresNumericWide(t *testing.T) {
+	tests := []struct {
+		hi int64
+		lo uint64
+		p  uint8
+		b  string
+	}{
+		{0, 0, 18, "0000000000000012"},
+		{-1, math.MaxUint64, 18, "0001fffb400000120064"},
+		{1, 0, 0, "00050004000000000734" + "1a5802e103bb0650"},
+		{0x4b3b4ca85a86c47a, 0x098a223fffffffff, 0, "000a0009000000000063" + "270f270f270f270f270f270f270f270f270f"},
+		{math.MaxInt64, math.MaxUint64, 18, "000b00050000001200011b66049f11fc1b0b" + "06c31ad9017316fc04210a8c"},
+		{math.MinInt64, 0, 18, "000b00054000001200011b66049f11fc1b0b" + "06c31ad9017316fc04210af0"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.b, func(t *testing.T) {
+			b := fpdecimal.AppendPostgresNumericWide(nil, tc.hi, tc.lo, tc.p)
+			if s := hex.EncodeToString(b); s != tc.b {
+				t.Error(s, tc.b)
+			}
+			if hi, lo, err := fpdecimal.ParsePostgresNumericWide(b, tc.p); err != nil || hi != tc.hi || lo != tc.lo {
+				t.Error(hi, lo, err)
+			}
+		})
+	}
+
+	errs := []struct {
+		name string
+		b    string
+		err  string
+	}{
+		{"1E+40", "0001000a000000000001", "overflow"},
+		{"2^127", "000b00050000001200011b66049f11fc1b0b" + "06c31ad9017316fc04210af0", "overflow"},
+		{"1E-19", "0001fffb000000130001", "more fraction digits than supported"},
+		{"NaN", "00000000c0000000", "postgres numeric: NaN"},
+	}
+	for _, tc := range errs {
+		t.Run(tc.name, func(t *testing.T) {
+			b, _ := hex.DecodeString(tc.b)
+			if hi, lo, err := fpdecimal.ParsePostgresNumericWide(b, 18); err == nil || err.Error() != tc.err || hi != 0 || lo != 0 {
+				t.Error(hi, lo, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,198 @@
+package fpdecimal
+
+import "math/bits"
+
+// PowRound returns fixed-point decimal v of p fractions raised to integer power n.
+// Power is by squaring, each product is 128 bits and rounded by mode.
+// Rounding errors of steps add up, so result can differ from exact power by more than one minor unit.
+// Negative n is reciprocal of power, rounded again.
+func PowRound(v int64, n int, p uint8, mode RoundingMode) (int64, error) {
+	return powRound(v, n, pow10[p], mode, MulDivRound)
+}
+
+// PowRoundUint is PowRound for uint64.
+func PowRoundUint(v uint64, n int, p uint8, mode RoundingMode) (uint64, error) {
+	return powRound(v, n, uint64(pow10[p]), mode, MulDivRoundUint)
+}
+
+// powRound raises v to power n by squaring, m is 10^p and mulDiv is rounded product.
+func powRound[T int64 | uint64](v T, n int, m T, mode RoundingMode, mulDiv func(a, b, c T, mode RoundingMode) (T, error)) (T, error) {
+	k := uint(n)
+	if n < 0 {
+		k = -k
+	}
+
+	r, b := m, v
+	for k > 0 {
+		var err error
+		if k&1 == 1 {
+			if r, err = mulDiv(r, b, m, mode); err != nil {
+				return 0, err
+			}
+		}
+		if k >>= 1; k > 0 {
+			if b, err = mulDiv(b, b, m, mode); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	if n < 0 {
+		return mulDiv(m, m, r, mode)
+	}
+	return r, nil
+}
+
+// SqrtRound returns square root of fixed-point decimal v of p fractions, rounded by mode.
+// It is integer square root of v×10^p by Newton's method, so result always fits.
+// Negative v is error.
+func SqrtRound(v int64, p uint8, mode RoundingMode) (int64, error) {
+	if v < 0 {
+		return 0, errNegative
+	}
+	return int64(SqrtRoundUint(uint64(v), p, mode)), nil
+}
+
+// SqrtRoundUint is SqrtRound for uint64.
+// Root of v×10^p is below 2^63, so result always fits.
+func SqrtRoundUint(v uint64, p uint8, mode RoundingMode) uint64 {
+	if v == 0 {
+		return 0
+	}
+
+	hi, lo := bits.Mul64(v, uint64(pow10[p]))
+
+	// start above root, iterations decrease to floor of root
+	n := 128 - bits.LeadingZeros64(hi)
+	if hi == 0 {
+		n = 64 - bits.LeadingZeros64(lo)
+	}
+	x := uint64(1) << ((n + 1) / 2)
+	for {
+		// x is not below floor of root, which is greater than hi, so division does not overflow
+		q, _ := bits.Div64(hi, lo, x)
+		y := (x + q) / 2
+		if y >= x {
+			break
+		}
+		x = y
+	}
+
+	// remainder is at most 2x, root of integer is never half, so only comparison of remainder to x matters
+	var r, d uint64 = 0, 3
+	switch rem := lo - x*x; {
+	case rem == 0:
+	case rem <= x:
+		r = 1
+	default:
+		r = 2
+	}
+	if mode.roundUp(x, r, d, false) {
+		x++
+	}
+	return x
+}
+
+// PowRoundWide is PowRound for two's complement of high and low bits, p is at most 38.
+// Each product is 256 bits.
+func PowRoundWide(hi int64, lo uint64, n int, p uint8, mode RoundingMode) (int64, uint64, error) {
+	mhi, mlo := pow10Wide(int(p))
+	uhi, ulo, neg := absWide(hi, lo)
+
+	k := uint(n)
+	if n < 0 {
+		k = -k
+	}
+
+	// sign of power is sign of v for odd n, rounding of each step follows it
+	rneg := neg && k&1 == 1
+	rhi, rlo, bhi, blo := mhi, mlo, uhi, ulo
+	for k > 0 {
+		var err error
+		if k&1 == 1 {
+			if rhi, rlo, err = mulDivRoundWide(rhi, rlo, bhi, blo, mhi, mlo, rneg, mode); err != nil {
+				return 0, 0, err
+			}
+		}
+		if k >>= 1; k > 0 {
+			if bhi, blo, err = mulDivRoundWide(bhi, blo, bhi, blo, mhi, mlo, false, mode); err != nil {
+				return 0, 0, err
+			}
+		}
+	}
+
+	if n < 0 {
+		if rhi == 0 && rlo == 0 {
+			return 0, 0, errDivisionByZero
+		}
+		var err error
+		if rhi, rlo, err = mulDivRoundWide(mhi, mlo, mhi, mlo, rhi, rlo, rneg, mode); err != nil {
+			return 0, 0, err
+		}
+	}
+	return signedWide(rhi, rlo, rneg)
+}
+
+// SqrtRoundWide is SqrtRound for two's complement of high and low bits, p is at most 38.
+// Root of v×10^p is below 2^127, so result fits unless it is rounded up to 2^127.
+func SqrtRoundWide(hi int64, lo uint64, p uint8, mode RoundingMode) (int64, uint64, error) {
+	if hi < 0 {
+		return 0, 0, errNegative
+	}
+	if hi == 0 && lo == 0 {
+		return 0, 0, nil
+	}
+
+	mhi, mlo := pow10Wide(int(p))
+	u := mulWide(uint64(hi), lo, mhi, mlo)
+
+	// start above root, iterations decrease to floor of root
+	n := 256
+	for _, w := range u {
+		if w != 0 {
+			n -= bits.LeadingZeros64(w)
+			break
+		}
+		n -= 64
+	}
+	var xhi, xlo uint64
+	if e := (n + 1) / 2; e < 64 {
+		xlo = 1 << e
+	} else {
+		xhi = 1 << (e - 64)
+	}
+	for {
+		// x is not below floor of root, so quotient fits 128 bits
+		qhi, qlo, _, _ := quoRem256(u, xhi, xlo)
+		slo, c := bits.Add64(xlo, qlo, 0)
+		shi, c := bits.Add64(xhi, qhi, c)
+		yhi, ylo := c<<63|shi>>1, shi<<63|slo>>1
+		if !lessWide(yhi, ylo, xhi, xlo) {
+			break
+		}
+		xhi, xlo = yhi, ylo
+	}
+
+	// remainder is at most 2x, root of integer is never half, so only comparison of remainder to x matters
+	s := mulWide(xhi, xlo, xhi, xlo)
+	var b uint64
+	var rem [4]uint64
+	rem[3], b = bits.Sub64(u[3], s[3], 0)
+	rem[2], b = bits.Sub64(u[2], s[2], b)
+	rem[1], _ = bits.Sub64(u[1], s[1], b)
+
+	var r, d uint64 = 0, 3
+	switch {
+	case rem[1] == 0 && rem[2] == 0 && rem[3] == 0:
+	case rem[1] == 0 && !lessWide(xhi, xlo, rem[2], rem[3]):
+		r = 1
+	default:
+		r = 2
+	}
+	if mode.roundUp(xlo, r, d, false) {
+		var c uint64
+		xlo, c = bits.Add64(xlo, 1, 0)
+		xhi += c
+	}
+	return signedWide(xhi, xlo, false)
+}
@@ -0,0 +1,342 @@
+package fpdecimal_test
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/nikolaydubina/fpdecimal"
+)
+
+func TestPowRound(t *testing.T) {
+	tests := []struct {
+		v    int64
+		n    int
+		p    uint8
+		mode fpdecimal.RoundingMode
+		q    int64
+	}{
+		{0, 0, 3, fpdecimal.ToNearestEven, 1_000},
+		{0, 5, 3, fpdecimal.ToNearestEven, 0},
+		{1_500, 0, 3, fpdecimal.ToNearestEven, 1_000},
+		{1_500, 1, 3, fpdecimal.ToNearestEven, 1_500},
+		{1_500, 2, 3, fpdecimal.ToNearestEven, 2_250},
+		{-1_500, 3, 3, fpdecimal.ToNearestEven, -3_375},
+		{1_050, 10, 3, fpdecimal.ToNearestEven, 1_624}, // exact is 1.628894627
+		{1_050, 10, 3, fpdecimal.ToZero, 1_623},
+		{1_050_000, 10, 6, fpdecimal.ToNearestEven, 1_628_894},
+		{2, 62, 0, fpdecimal.ToZero, 1 << 62},
+		{-2, 63, 0, fpdecimal.ToZero, math.MinInt64},
+		{1_000, math.MaxInt, 3, fpdecimal.ToZero, 1_000},
+		{-1_000, math.MaxInt, 3, fpdecimal.ToZero, -1_000},
+		{999, 1_000_000, 3, fpdecimal.ToZero, 0},
+		{2_000, -1, 3, fpdecimal.ToNearestEven, 500},
+		{3_000, -1, 3, fpdecimal.ToNearestEven, 333},
+		{3_000, -1, 3, fpdecimal.AwayFromZero, 334},
+		{-2_000, -3, 3, fpdecimal.ToNearestEven, -125},
+		{1_000, math.MinInt, 3, fpdecimal.ToNearestEven, 1_000},
+	}
+	for _, tc := range tests {
+		t.Run(fmt.Sprint(tc.v, tc.n, tc.p, tc.mode), func(t *testing.T) {
+			if q, err := fpdecimal.PowRound(tc.v, tc.n, tc.p, tc.mode); err != nil || q != tc.q {
+				t.Error(q, tc.q, err)
+			}
+		})
+	}
+}
+
+func TestPowRound_Error(t *testing.T) {
+	tests := []struct {
+		v int64
+		n int
+	}{
+		{2, 63},
+		{-2, 64},
+		{2_000, 54},
+		{1_001, 1_000_000},
+		{0, -1},
+		{999, -1_000_000},
+	}
+	for _, tc := range tests {
+		t.Run(fmt.Sprint(tc.v, tc.n), func(t *testing.T) {
+			p := uint8(3)
+			if tc.v == 2 || tc.v == -2 {
+				p = 0
+			}
+			if q, err := fpdecimal.PowRound(tc.v, tc.n, p, fpdecimal.ToZero); err == nil || q != 0 {
+				t.Error(q, err)
+			}
+		})
+	}
+}
+
+func TestSqrtRound(t *testing.T) {
+	// in order of roundingModes
+	tests := []struct {
+		v  int64
+		p  uint8
+		vs [6]int64
+	}{
+		{0, 3, [6]int64{0, 0, 0, 0, 0, 0}},
+		{1, 0, [6]int64{1, 1, 1, 1, 1, 1}},
+		{2, 0, [6]int64{1, 1, 1, 2, 1, 2}},
+		{3, 0, [6]int64{2, 2, 1, 2, 1, 2}},
+		{4_000, 3, [6]int64{2_000, 2_000, 2_000, 2_000, 2_000, 2_000}},
+		{2_000, 3, [6]int64{1_414, 1_414, 1_414, 1_415, 1_414, 1_415}},
+		{2_000_000, 6, [6]int64{1_414_214, 1_414_214, 1_414_213, 1_414_214, 1_414_213, 1_414_214}},
+		{1, 3, [6]int64{32, 32, 31, 32, 31, 32}},
+		{math.MaxInt64, 0, [6]int64{3_037_000_500, 3_037_000_500, 3_037_000_499, 3_037_000_500, 3_037_000_499, 3_037_000_500}},
+		{math.MaxInt64, 18, [6]int64{3_037_000_499_976_049_692, 3_037_000_499_976_049_692, 3_037_000_499_976_049_692, 3_037_000_499_976_049_693, 3_037_000_499_976_049_692, 3_037_000_499_976_049_693}},
+	}
+	for _, tc := range tests {
+		for i, mode := range roundingModes {
+			t.Run(fmt.Sprint(tc.v, tc.p, mode), func(t *testing.T) {
+				if v, err := fpdecimal.SqrtRound(tc.v, tc.p, mode); err != nil || v != tc.vs[i] {
+					t.Error(v, tc.vs[i], err)
+				}
+			})
+		}
+	}
+
+	if v, err := fpdecimal.SqrtRound(-1, 3, fpdecimal.ToZero); err == nil || v != 0 {
+		t.Error(v, err)
+	}
+}
+
+func TestPowRoundUint(t *testing.T) {
+	tests := []struct {
+		v    uint64
+		n    int
+		mode fpdecimal.RoundingMode
+		q    uint64
+		err  bool
+	}{
+		{1_500, 2, fpdecimal.ToNearestEven, 2_250, false},
+		{1_050, 10, fpdecimal.ToNearestEven, 1_624, false},
+		{3_000, -1, fpdecimal.AwayFromZero, 334, false},
+		{4_294_967_296_000, 2, fpdecimal.ToZero, 0, true},
+		{4_294_967_295, 2, fpdecimal.ToZero, 18_446_744_065_119_617, false},
+		{0, -1, fpdecimal.ToZero, 0, true},
+	}
+	for _, tc := range tests {
+		t.Run(fmt.Sprint(tc.v, tc.n, tc.mode), func(t *testing.T) {
+			if q, err := fpdecimal.PowRoundUint(tc.v, tc.n, 3, tc.mode); (err != nil) != tc.err || q != tc.q {
+				t.Error(q, tc.q, err)
+			}
+		})
+	}
+}
+
+func TestSqrtRoundUint(t *testing.T) {
+	tests := []struct {
+		v    uint64
+		p    uint8
+		mode fpdecimal.RoundingMode
+		q    uint64
+	}{
+		{0, 3, fpdecimal.ToZero, 0},
+		{2_000, 3, fpdecimal.ToNearestEven, 1_414},
+		{math.MaxUint64, 0, fpdecimal.ToZero, math.MaxUint32},
+		{math.MaxUint64, 0, fpdecimal.ToPositiveInf, math.MaxUint32 + 1},
+		{math.MaxUint64, 18, fpdecimal.ToZero, 4_294_967_295_999_999_999},
+	}
+	for _, tc := range tests {
+		t.Run(fmt.Sprint(tc.v, tc.p, tc.mode), func(t *testing.T) {
+			if q := fpdecimal.SqrtRoundUint(tc.v, tc.p, tc.mode); q != tc.q {
+				t.Error(q, tc.q)
+			}
+		})
+	}
+}
+
+func TestPowRoundWide(t *testing.T) {
+	tests := []struct {
+		v    string
+		n    int
+		mode fpdecimal.RoundingMode
+		q    string
+		err  string
+	}{
+		{"1.5", 2, fpdecimal.ToNearestEven, "2.25", ""},
+		{"-1.5", 3, fpdecimal.ToZero, "-3.375", ""},
+		{"3", -1, fpdecimal.ToNearestEven, "0.333333333333333333", ""},
+		{"-3", -1, fpdecimal.AwayFromZero, "-0.333333333333333334", ""},
+		{"1000000000", 2, fpdecimal.ToZero, "1000000000000000000", ""},
+		{"20000000000", 2, fpdecimal.ToZero, "", "overflow"},
+		{"0", -1, fpdecimal.ToZero, "", "division by zero"},
+	}
+	for _, tc := range tests {
+		t.Run(fmt.Sprint(tc.v, tc.n, tc.mode), func(t *testing.T) {
+			hi, lo, _ := fpdecimal.ParseFixedPointDecimalWide([]byte(tc.v), 18)
+			qhi, qlo, err := fpdecimal.PowRoundWide(hi, lo, tc.n, 18, tc.mode)
+			if tc.err != "" {
+				if err == nil || err.Error() != tc.err || qhi != 0 || qlo != 0 {
+					t.Error(qhi, qlo, err)
+				}
+				return
+			}
+			if q := fpdecimal.FixedPointDecimalWideToString(qhi, qlo, 18); err != nil || q != tc.q {
+				t.Error(q, tc.q, err)
+			}
+		})
+	}
+}
+
+func TestSqrtRoundWide(t *testing.T) {
+	tests := []struct {
+		v    string
+		mode fpdecimal.RoundingMode
+		q    string
+	}{
+		{"0", fpdecimal.ToZero, "0"},
+		{"2", fpdecimal.ToZero, "1.414213562373095048"},
+		{"2", fpdecimal.ToPositiveInf, "1.414213562373095049"},
+		{"170141183460469231731.687303715884105727", fpdecimal.ToZero, "13043817825.332782212349571806"},
+		{"170141183460469231731.687303715884105727", fpdecimal.ToNearestEven, "13043817825.332782212349571806"},
+	}
+	for _, tc := range tests {
+		t.Run(fmt.Sprint(tc.v, tc.mode), func(t *testing.T) {
+			hi, lo, _ := fpdecimal.ParseFixedPointDecimalWide([]byte(tc.v), 18)
+			qhi, qlo, err := fpdecimal.SqrtRoundWide(hi, lo, 18, tc.mode)
+			if q := fpdecimal.FixedPointDecimalWideToString(qhi, qlo, 18); err != nil || q != tc.q {
+				t.Error(q, tc.q, err)
+			}
+		})
+	}
+
+	if hi, lo, err := fpdecimal.SqrtRoundWide(-1, math.MaxUint64, 18, fpdecimal.ToZero); err == nil || hi != 0 || lo != 0 {
+		t.Error(hi, lo, err)
+	}
+}
+
+func FuzzSqrtRoundWide(f *testing.F) {
+	f.Add(int64(0), uint64(0), uint8(0))
+	f.Add(int64(0), uint64(2_000), uint8(3))
+	f.Add(int64(math.MaxInt64), uint64(math.MaxUint64), uint8(18))
+	f.Add(int64(math.MaxInt64), uint64(math.MaxUint64), uint8(38))
+	f.Fuzz(func(t *testing.T, hi int64, lo uint64, p uint8) {
+		if hi < 0 || p > 38 {
+			t.Skip()
+		}
+
+		n := new(big.Int).Mul(wideBig(hi, lo), new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(p)), nil))
+		x := new(big.Int).Sqrt(n)
+
+		qhi, qlo, err := fpdecimal.SqrtRoundWide(hi, lo, p, fpdecimal.ToZero)
+		if err != nil || wideBig(qhi, qlo).Cmp(x) != 0 {
+			t.Error(hi, lo, p, qhi, qlo, x, err)
+		}
+
+		// nearest is x+1 when n > x² + x
+		w := new(big.Int).Mul(x, x)
+		w.Add(w, x)
+		if w.Cmp(n) < 0 {
+			x.Add(x, big.NewInt(1))
+		}
+		qhi, qlo, err = fpdecimal.SqrtRoundWide(hi, lo, p, fpdecimal.ToNearestEven)
+		if err != nil || wideBig(qhi, qlo).Cmp(x) != 0 {
+			t.Error(hi, lo, p, qhi, qlo, x, err)
+		}
+	})
+}
+
+func FuzzSqrtRound(f *testing.F) {
+	tests := []int64{0, 1, 2, 3, 2_000, 123456789, math.MaxInt64}
+	for _, tc := range tests {
+		for _, p := range []uint8{0, 3, 18} {
+			f.Add(tc, p)
+		}
+	}
+	f.Fuzz(func(t *testing.T, v int64, p uint8) {
+		if v < 0 || p > 18 {
+			t.Skip()
+		}
+
+		n := new(big.Int).Mul(big.NewInt(v), new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(p)), nil))
+		x := new(big.Int).Sqrt(n)
+
+		q, err := fpdecimal.SqrtRound(v, p, fpdecimal.ToZero)
+		if err != nil || big.NewInt(q).Cmp(x) != 0 {
+			t.Error(v, p, q, x, err)
+		}
+
+		// nearest is x+1 when n > x² + x
+		w := new(big.Int).Mul(x, x)
+		w.Add(w, x)
+		if w.Cmp(n) < 0 {
+			x.Add(x, big.NewInt(1))
+		}
+		if q, err := fpdecimal.SqrtRound(v, p, fpdecimal.ToNearestEven); err != nil || big.NewInt(q).Cmp(x) != 0 {
+			t.Error(v, p, q, x, err)
+		}
+	})
+}
+
+func FuzzPowRound(f *testing.F) {
+	tests := []struct {
+		v int64
+		n int8
+	}{
+		{0, 0},
+		{1_500, 3},
+		{1_050, 10},
+		{-999, 7},
+		{2_000, -3},
+	}
+	for _, tc := range tests {
+		f.Add(tc.v, tc.n)
+	}
+	f.Fuzz(func(t *testing.T, v int64, n int8) {
+		q, err := fpdecimal.PowRound(v, int(n), 3, fpdecimal.ToZero)
+		if err != nil || n <= 0 {
+			return
+		}
+
+		// rounding of each step toward zero makes magnitude not above exact power
+		e := new(big.Rat).SetFrac(new(big.Int).Exp(big.NewInt(v), big.NewInt(int64(n)), nil), new(big.Int).Exp(big.NewInt(1_000), big.NewInt(int64(n)-1), nil))
+		if e.Sign()*big.NewInt(q).Sign() < 0 || new(big.Rat).Abs(new(big.Rat).SetInt64(q)).Cmp(new(big.Rat).Abs(e)) > 0 {
+			t.Error(v, n, q, e.FloatString(3))
+		}
+	})
+}
+
+func FuzzPowRoundWide(f *testing.F) {
+	f.Add(int64(1_500), int8(3))
+	f.Add(int64(-999), int8(7))
+	f.Add(int64(2_000), int8(-3))
+	f.Fuzz(func(t *testing.T, v int64, n int8) {
+		q, err := fpdecimal.PowRound(v, int(n), 3, fpdecimal.ToNearestEven)
+		if err != nil {
+			return
+		}
+
+		// wide range is larger, so results that fit narrow are same
+		hi, lo, err := fpdecimal.PowRoundWide(v>>63, uint64(v), int(n), 3, fpdecimal.ToNearestEven)
+		if err != nil || hi != q>>63 || lo != uint64(q) {
+			t.Error(v, n, q, hi, lo, err)
+		}
+	})
+}
+
+func BenchmarkPowRound(b *testing.B) {
+	var v int64
+	var err error
+	for n := 0; n < b.N; n++ {
+		v, err = fpdecimal.PowRound(1_050, 10, 3, fpdecimal.ToNearestEven)
+	}
+	if v != 1_624 || err != nil {
+		b.Error(v, err)
+	}
+}
+
+func BenchmarkSqrtRound(b *testing.B) {
+	var v int64
+	var err error
+	for n := 0; n < b.N; n++ {
+		v, err = fpdecimal.SqrtRound(2_000, 3, fpdecimal.ToNearestEven)
+	}
+	if v != 1_414 || err != nil {
+		b.Error(v, err)
+	}
+}
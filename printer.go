@@ -1,10 +1,12 @@
 package fpdecimal
 
 import (
+	"math"
+	"math/bits"
 	"strconv"
 )
 
-const zeroPrefix = "0.000000000000000000000000000000000000"
+const zeroPrefix = "0.00000000000000000000000000000000000000"
 
 // FixedPointDecimalToString formats fixed-point decimal to string
 func FixedPointDecimalToString(v int64, p uint8) string {
@@ -28,27 +30,33 @@ func AppendFixedPointDecimal(b []byte, v int64, p uint8) []byte {
 		return strconv.AppendInt(b, v, 10)
 	}
 
+	u := uint64(v)
 	if v < 0 {
-		v = -v
+		u = -u
 		b = append(b, '-')
 	}
 
-	// strconv.AppendInt is very efficient.
+	// strconv.AppendUint is very efficient.
 	// Efficient converting int64 to ASCII is not as trivial.
-	s := uint8(len(b))
-	b = strconv.AppendInt(b, v, 10)
+	s := len(b)
+	b = strconv.AppendUint(b, u, 10)
 
+	return appendFractions(b, s, p)
+}
+
+// appendFractions places decimal point before p last digits of b[s:] and removes trailing zeros.
+func appendFractions(b []byte, s int, p uint8) []byte {
 	// has whole?
-	if uint8(len(b))-s > p {
+	if len(b)-s > int(p) {
 		// place decimal point
-		i := uint8(len(b)) - p
+		i := len(b) - int(p)
 		b = append(b, 0)
 		copy(b[i+1:], b[i:])
 		b[i] = '.'
 	} else {
 		// append zeroes and decimal point
-		i := 2 + p - (uint8(len(b)) - s)
-		for j := uint8(0); j < i; j++ {
+		i := 2 + int(p) - (len(b) - s)
+		for j := 0; j < i; j++ {
 			b = append(b, 0)
 		}
 		copy(b[s+i:], b[s:])
@@ -56,11 +64,9 @@ func AppendFixedPointDecimal(b []byte, v int64, p uint8) []byte {
 	}
 
 	// remove trailing zeros
-	n := 0
-	for i, q := range b {
-		if q != '0' {
-			n = i
-		}
+	n := len(b) - 1
+	for b[n] == '0' {
+		n--
 	}
 	if b[n] == '.' {
 		n--
@@ -69,3 +75,86 @@ func AppendFixedPointDecimal(b []byte, v int64, p uint8) []byte {
 
 	return b
 }
+
+// FixedPointDecimalUintToString formats unsigned fixed-point decimal to string
+func FixedPointDecimalUintToString(v uint64, p uint8) string {
+	// max uint64: 18446744073709551.615
+	// max bytes uint64: 21
+	b := make([]byte, 0, 21)
+	b = AppendFixedPointDecimalUint(b, v, p)
+	return string(b)
+}
+
+// AppendFixedPointDecimalUint appends formatted unsigned fixed-point decimal to destination buffer.
+func AppendFixedPointDecimalUint(b []byte, v uint64, p uint8) []byte {
+	if v == 0 {
+		return append(b, '0')
+	}
+
+	s := len(b)
+	b = strconv.AppendUint(b, v, 10)
+	if p == 0 {
+		return b
+	}
+	return appendFractions(b, s, p)
+}
+
+// FixedPointDecimalWideToString formats 128-bit fixed-point decimal to string.
+// Value is two's complement of high and low bits.
+func FixedPointDecimalWideToString(hi int64, lo uint64, p uint8) string {
+	// max bytes 128-bit: 41
+	b := make([]byte, 0, 41)
+	b = AppendFixedPointDecimalWide(b, hi, lo, p)
+	return string(b)
+}
+
+// AppendFixedPointDecimalWide appends formatted 128-bit fixed-point decimal to destination buffer.
+// Value is two's complement of high and low bits.
+func AppendFixedPointDecimalWide(b []byte, hi int64, lo uint64, p uint8) []byte {
+	if (hi == 0 && lo <= math.MaxInt64) || (hi == -1 && lo > math.MaxInt64) {
+		return AppendFixedPointDecimal(b, int64(lo), p)
+	}
+
+	u := uint64(hi)
+	if hi < 0 {
+		var c uint64
+		lo, c = bits.Sub64(0, lo, 0)
+		u, _ = bits.Sub64(0, u, c)
+		b = append(b, '-')
+	}
+
+	// 128 bits are at most 39 digits, printed by 19 digits
+	const e19 = 10_000_000_000_000_000_000
+	s := len(b)
+	q1, r1 := u/e19, u%e19
+	q0, r0 := bits.Div64(r1, lo, e19)
+	switch {
+	case q1 == 0 && q0 == 0:
+		b = strconv.AppendUint(b, r0, 10)
+	case q1 == 0:
+		b = strconv.AppendUint(b, q0, 10)
+		b = appendDigits(b, r0, 19)
+	default:
+		q, r := bits.Div64(q1, q0, e19)
+		b = strconv.AppendUint(b, q, 10)
+		b = appendDigits(b, r, 19)
+		b = appendDigits(b, r0, 19)
+	}
+
+	if p == 0 {
+		return b
+	}
+	return appendFractions(b, s, p)
+}
+
+// appendDigits appends n digits of v, with leading zeros.
+func appendDigits(b []byte, v uint64, n int) []byte {
+	s := len(b)
+	b = strconv.AppendUint(b, v, 10)
+	if d := n - (len(b) - s); d > 0 {
+		b = append(b, zeroPrefix[2:2+d]...)
+		copy(b[s+d:], b[s:])
+		copy(b[s:], zeroPrefix[2:2+d])
+	}
+	return b
+}
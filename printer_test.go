@@ -3,6 +3,7 @@ package fpdecimal_test
 import (
 	"fmt"
 	"math"
+	"math/big"
 	"math/rand"
 	"strconv"
 	"testing"
@@ -122,3 +123,179 @@ func BenchmarkAppendFixedPointDecimal(b *testing.B) {
 		})
 	}
 }
+
+func TestAppendFixedPointDecimal_LongBuffer(t *testing.T) {
+	b := make([]byte, 300, 400)
+	for i := range b {
+		b[i] = '0'
+	}
+
+	b = fpdecimal.AppendFixedPointDecimal(b, -12300, 3)
+	b = fpdecimal.AppendFixedPointDecimal(b, 5, 3)
+	if s := string(b[300:]); s != "-12.30.005" {
+		t.Error(s)
+	}
+}
+
+func TestAppendFixedPointDecimal_MinInt64(t *testing.T) {
+	if s := fpdecimal.FixedPointDecimalToString(math.MinInt64, 3); s != "-9223372036854775.808" {
+		t.Error(s)
+	}
+}
+
+// wideBig is value of two's complement of high and low bits.
+func wideBig(hi int64, lo uint64) *big.Int {
+	v := new(big.Int).Lsh(big.NewInt(hi), 64)
+	return v.Or(v, new(big.Int).SetUint64(lo))
+}
+
+func TestFixedPointDecimalWideToString(t *testing.T) {
+	tests := []struct {
+		hi int64
+		lo uint64
+		p  uint8
+		s  string
+	}{
+		{0, 0, 18, "0"},
+		{0, 1, 18, "0.000000000000000001"},
+		{-1, math.MaxUint64, 18, "-0.000000000000000001"},
+		{0, 1_500_000_000_000_000_000, 18, "1.5"},
+		{0, math.MaxInt64, 3, "9223372036854775.807"},
+		{0, math.MaxInt64 + 1, 3, "9223372036854775.808"},
+		{-1, math.MaxInt64 + 1, 3, "-9223372036854775.808"},
+		{0, math.MaxUint64, 0, "18446744073709551615"},
+		{0, 10_000_000_000_000_000_000, 0, "10000000000000000000"},
+		{1, 0, 18, "18.446744073709551616"},
+		{0x21e, 0x19e0c9bab2400000, 18, "10000"},
+		{0x21e, 0x19e0c9bab2400000, 0, "10000000000000000000000"},
+		{math.MaxInt64, math.MaxUint64, 18, "170141183460469231731.687303715884105727"},
+		{math.MinInt64, 0, 18, "-170141183460469231731.687303715884105728"},
+		{math.MinInt64, 0, 0, "-170141183460469231731687303715884105728"},
+		{math.MaxInt64, math.MaxUint64, 38, "1.70141183460469231731687303715884105727"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.s, func(t *testing.T) {
+			if s := fpdecimal.FixedPointDecimalWideToString(tc.hi, tc.lo, tc.p); s != tc.s {
+				t.Error(s, tc.s)
+			}
+
+			hi, lo, err := fpdecimal.ParseFixedPointDecimalWide([]byte(tc.s), tc.p)
+			if err != nil || hi != tc.hi || lo != tc.lo {
+				t.Error(hi, lo, err)
+			}
+		})
+	}
+}
+
+func FuzzFixedPointDecimalWideToString(f *testing.F) {
+	tests := [][2]uint64{
+		{0, 0},
+		{0, 1},
+		{1, 0},
+		{math.MaxUint64, math.MaxUint64},
+		{1 << 63, 0},
+	}
+	for _, tc := range tests {
+		for _, p := range []uint8{0, 3, 18} {
+			f.Add(tc[0], tc[1], p)
+		}
+	}
+	f.Fuzz(func(t *testing.T, hi, lo uint64, p uint8) {
+		if p > 38 {
+			t.Skip()
+		}
+
+		s := fpdecimal.FixedPointDecimalWideToString(int64(hi), lo, p)
+
+		q, ok := new(big.Rat).SetString(s)
+		if !ok {
+			t.Fatal(s)
+		}
+		v := new(big.Rat).SetFrac(wideBig(int64(hi), lo), new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(p)), nil))
+		if q.Cmp(v) != 0 {
+			t.Error(s, v.FloatString(int(p)))
+		}
+
+		h, l, err := fpdecimal.ParseFixedPointDecimalWide([]byte(s), p)
+		if err != nil || h != int64(hi) || l != lo {
+			t.Error(s, h, l, err)
+		}
+
+		if int64(hi) == int64(lo)>>63 {
+			if a := fpdecimal.FixedPointDecimalToString(int64(lo), p); a != s {
+				t.Error(a, s)
+			}
+		}
+	})
+}
+
+func BenchmarkAppendFixedPointDecimalWide(b *testing.B) {
+	tests := []struct {
+		name string
+		hi   int64
+		lo   uint64
+	}{
+		{"small", 0, 1_234_560_000_000_000_000},
+		{"large", math.MaxInt64 / 3, math.MaxUint64 / 7},
+	}
+
+	d := make([]byte, 0, 41)
+	for _, tc := range tests {
+		b.Run(tc.name, func(b *testing.B) {
+			for n := 0; n < b.N; n++ {
+				d = fpdecimal.AppendFixedPointDecimalWide(d[:0], tc.hi, tc.lo, 18)
+				if len(d) == 0 {
+					b.Error("empty str")
+				}
+			}
+		})
+	}
+}
+
+func TestFixedPointDecimalUintToString(t *testing.T) {
+	tests := []struct {
+		v uint64
+		p uint8
+		s string
+	}{
+		{0, 3, "0"},
+		{1, 3, "0.001"},
+		{1500, 3, "1.5"},
+		{math.MaxUint64, 0, "18446744073709551615"},
+		{math.MaxUint64, 3, "18446744073709551.615"},
+		{math.MaxUint64, 20, "0.18446744073709551615"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.s, func(t *testing.T) {
+			if s := fpdecimal.FixedPointDecimalUintToString(tc.v, tc.p); s != tc.s {
+				t.Error(s, tc.s)
+			}
+		})
+	}
+}
+
+func FuzzFixedPointDecimalUintToString(f *testing.F) {
+	tests := []uint64{0, 1, 1000, 123456789, math.MaxInt64 + 1, math.MaxUint64}
+	for _, tc := range tests {
+		for _, p := range []uint8{0, 3, 18} {
+			f.Add(tc, p)
+		}
+	}
+	f.Fuzz(func(t *testing.T, v uint64, p uint8) {
+		if p > 19 {
+			t.Skip()
+		}
+
+		s := fpdecimal.FixedPointDecimalUintToString(v, p)
+
+		if u, err := fpdecimal.ParseFixedPointDecimalUint([]byte(s), p); err != nil || u != v {
+			t.Error(s, u, v, err)
+		}
+
+		if v <= math.MaxInt64 {
+			if a := fpdecimal.FixedPointDecimalToString(int64(v), p); a != s {
+				t.Error(a, s)
+			}
+		}
+	})
+}
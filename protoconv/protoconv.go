@@ -0,0 +1,100 @@
+// Package protoconv converts fixed-point decimals to and from wire shapes of
+// Protocol Buffers well-known types google.type.Money and google.type.Decimal.
+//
+// Money is represented by its units and nanos fields, Decimal by its value field.
+// This keeps fpdecimal free of protobuf dependency.
+// All conversions are lossless, values that can not be represented exactly result in error.
+package protoconv
+
+import (
+	"errors"
+	"math/bits"
+
+	"github.com/nikolaydubina/fpdecimal"
+	"github.com/nikolaydubina/fpdecimal/fp3"
+	"github.com/nikolaydubina/fpdecimal/fp6"
+)
+
+var (
+	ErrNanosOutOfRange   = errors.New("nanos out of range")
+	ErrNanosSignMismatch = errors.New("units and nanos have different signs")
+)
+
+// nanosDigits is number of fractional digits in google.type.Money nanos.
+const nanosDigits = 9
+
+// FP3ToMoney returns units and nanos of google.type.Money.
+func FP3ToMoney(d fp3.Decimal) (units int64, nanos int32) {
+	units, frac := d.Split()
+	return units, toNanos(frac, 3)
+}
+
+// FP3FromMoney converts units and nanos of google.type.Money.
+func FP3FromMoney(units int64, nanos int32) (fp3.Decimal, error) {
+	v, err := fromMoney(units, nanos, 3)
+	if err != nil {
+		return fp3.Zero, err
+	}
+	return fp3.FromIntScaled(v), nil
+}
+
+// FP3ToDecimal returns value of google.type.Decimal.
+func FP3ToDecimal(d fp3.Decimal) string { return d.String() }
+
+// FP3FromDecimal converts value of google.type.Decimal.
+func FP3FromDecimal(s string) (fp3.Decimal, error) {
+	v, err := fpdecimal.ParseFixedPointDecimalExp([]byte(s), 3)
+	return fp3.FromIntScaled(v), err
+}
+
+// FP6ToMoney returns units and nanos of google.type.Money.
+func FP6ToMoney(d fp6.Decimal) (units int64, nanos int32) {
+	units, frac := d.Split()
+	return units, toNanos(frac, 6)
+}
+
+// FP6FromMoney converts units and nanos of google.type.Money.
+func FP6FromMoney(units int64, nanos int32) (fp6.Decimal, error) {
+	v, err := fromMoney(units, nanos, 6)
+	if err != nil {
+		return fp6.Zero, err
+	}
+	return fp6.FromIntScaled(v), nil
+}
+
+// FP6ToDecimal returns value of google.type.Decimal.
+func FP6ToDecimal(d fp6.Decimal) string { return d.String() }
+
+// FP6FromDecimal converts value of google.type.Decimal.
+func FP6FromDecimal(s string) (fp6.Decimal, error) {
+	v, err := fpdecimal.ParseFixedPointDecimalExp([]byte(s), 6)
+	return fp6.FromIntScaled(v), err
+}
+
+// toNanos returns fractions of p digits as nanos, with same sign as units, as required by google.type.Money.
+func toNanos(frac int64, p uint8) int32 {
+	// fractions have less digits than nanos, so they always fit
+	n, _ := fpdecimal.FixedPointDecimalToUnscaled(frac, p, nanosDigits, nanosDigits)
+	return int32(n)
+}
+
+// fromMoney combines units and nanos into scaled value of p fractions.
+func fromMoney(units int64, nanos int32, p uint8) (int64, error) {
+	if nanos <= -1_000_000_000 || nanos >= 1_000_000_000 {
+		return 0, ErrNanosOutOfRange
+	}
+	if (units > 0 && nanos < 0) || (units < 0 && nanos > 0) {
+		return 0, ErrNanosSignMismatch
+	}
+
+	// units and nanos have same sign, so value is their sum of 9 fractions, which fits 128 bits
+	hi, lo, _ := fpdecimal.MulDivRoundWide(units>>63, uint64(units), 0, 1_000_000_000, 0, 1, fpdecimal.ToZero)
+	lo, c := bits.Add64(lo, uint64(nanos), 0)
+	hi += int64(nanos)>>31 + int64(c)
+
+	hi, lo, err := fpdecimal.FixedPointDecimalWideFromUnscaled(hi, lo, 38, nanosDigits, p)
+	if err != nil {
+		return 0, err
+	}
+	return fpdecimal.FixedPointDecimalFromWide(hi, lo, p, p, fpdecimal.ToZero)
+}
@@ -0,0 +1,250 @@
+package protoconv_test
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/nikolaydubina/fpdecimal/fp3"
+	"github.com/nikolaydubina/fpdecimal/fp6"
+	"github.com/nikolaydubina/fpdecimal/protoconv"
+)
+
+func TestFP3Money(t *testing.T) {
+	tests := []struct {
+		s     string
+		units int64
+		nanos int32
+	}{
+		{"0", 0, 0},
+		{"1", 1, 0},
+		{"-1", -1, 0},
+		{"12.5", 12, 500_000_000},
+		{"-12.5", -12, -500_000_000},
+		{"0.001", 0, 1_000_000},
+		{"-0.001", 0, -1_000_000},
+		{"9223372036854775.807", 9223372036854775, 807_000_000},
+		{"-9223372036854775.808", -9223372036854775, -808_000_000},
+	}
+	for _, tc := range tests {
+		t.Run(tc.s, func(t *testing.T) {
+			d, _ := fp3.FromString(tc.s)
+
+			units, nanos := protoconv.FP3ToMoney(d)
+			if units != tc.units || nanos != tc.nanos {
+				t.Error(units, nanos)
+			}
+
+			v, err := protoconv.FP3FromMoney(tc.units, tc.nanos)
+			if err != nil {
+				t.Error(err)
+			}
+			if v != d {
+				t.Error(v, d)
+			}
+		})
+	}
+}
+
+func TestFP6Money(t *testing.T) {
+	tests := []struct {
+		s     string
+		units int64
+		nanos int32
+	}{
+		{"0", 0, 0},
+		{"12.5", 12, 500_000_000},
+		{"-0.000001", 0, -1_000},
+		{"9223372036854.775807", 9223372036854, 775_807_000},
+		{"-9223372036854.775808", -9223372036854, -775_808_000},
+	}
+	for _, tc := range tests {
+		t.Run(tc.s, func(t *testing.T) {
+			d, _ := fp6.FromString(tc.s)
+
+			units, nanos := protoconv.FP6ToMoney(d)
+			if units != tc.units || nanos != tc.nanos {
+				t.Error(units, nanos)
+			}
+
+			v, err := protoconv.FP6FromMoney(tc.units, tc.nanos)
+			if err != nil {
+				t.Error(err)
+			}
+			if v != d {
+				t.Error(v, d)
+			}
+		})
+	}
+}
+
+func TestFromMoney_Error(t *testing.T) {
+	tests := []struct {
+		units int64
+		nanos int32
+		err   error
+	}{
+		{0, 1_000_000_000, protoconv.ErrNanosOutOfRange},
+		{0, -1_000_000_000, protoconv.ErrNanosOutOfRange},
+		{1, -1, protoconv.ErrNanosSignMismatch},
+		{-1, 1, protoconv.ErrNanosSignMismatch},
+		{0, 1, errors.New("more fraction digits than supported")},
+		{0, 1_000_001, errors.New("more fraction digits than supported")},
+		{9223372036854776, 0, errors.New("overflow")},
+		{9223372036854775, 808_000_000, errors.New("overflow")},
+		{-9223372036854775, -809_000_000, errors.New("overflow")},
+		{math.MaxInt64, 999_000_000, errors.New("overflow")},
+	}
+	for _, tc := range tests {
+		t.Run(fmt.Sprint(tc.units, tc.nanos), func(t *testing.T) {
+			v, err := protoconv.FP3FromMoney(tc.units, tc.nanos)
+			if err == nil || err.Error() != tc.err.Error() {
+				t.Error(err)
+			}
+			if v != fp3.Zero {
+				t.Error(v)
+			}
+		})
+	}
+}
+
+func TestFP3FromDecimal(t *testing.T) {
+	tests := []struct {
+		s string
+		v int64
+	}{
+		{"0", 0},
+		{"-1.500000000000", -1500},
+		{"1.5e3", 1_500_000},
+		{"1000e-6", 1},
+		{"-9223372036854775.808", -9223372036854775808},
+	}
+	for _, tc := range tests {
+		t.Run(tc.s, func(t *testing.T) {
+			v, err := protoconv.FP3FromDecimal(tc.s)
+			if err != nil {
+				t.Error(err)
+			}
+			if v.Scaled() != tc.v {
+				t.Error(v.Scaled(), tc.v)
+			}
+		})
+	}
+}
+
+func TestFP3FromDecimal_Error(t *testing.T) {
+	tests := []struct {
+		s   string
+		err string
+	}{
+		{"", "empty string"},
+		{"1e", "missing digits in exponent"},
+		{"1,5", "bad digit"},
+		{"1e-4", "more fraction digits than supported"},
+		{"1e16", "overflow"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.s, func(t *testing.T) {
+			v, err := protoconv.FP3FromDecimal(tc.s)
+			if err == nil || err.Error() != tc.err {
+				t.Error(err)
+			}
+			if v != fp3.Zero {
+				t.Error(v)
+			}
+		})
+	}
+}
+
+func TestFP6FromDecimal(t *testing.T) {
+	v, err := protoconv.FP6FromDecimal("-1.000001")
+	if err != nil || v.Scaled() != -1_000_001 {
+		t.Error(v, err)
+	}
+
+	if _, err := protoconv.FP6FromDecimal("1.0000001"); err == nil || err.Error() != "more fraction digits than supported" {
+		t.Error(err)
+	}
+}
+
+func FuzzFP3_Money(f *testing.F) {
+	tests := []int64{0, 1, 999, 1000, 1001, 123456789}
+	for _, tc := range tests {
+		f.Add(tc)
+		f.Add(-tc)
+	}
+	f.Fuzz(func(t *testing.T, a int64) {
+		d := fp3.FromIntScaled(a)
+		v, err := protoconv.FP3FromMoney(protoconv.FP3ToMoney(d))
+		if err != nil {
+			t.Error(err)
+		}
+		if v != d {
+			t.Error(v, d)
+		}
+	})
+}
+
+func FuzzFP6_Decimal(f *testing.F) {
+	tests := []int64{0, 1, 999, 1000, 1001, 123456789}
+	for _, tc := range tests {
+		f.Add(tc)
+		f.Add(-tc)
+	}
+	f.Fuzz(func(t *testing.T, a int64) {
+		d := fp6.FromIntScaled(a)
+		v, err := protoconv.FP6FromDecimal(protoconv.FP6ToDecimal(d))
+		if err != nil {
+			t.Error(err)
+		}
+		if v != d {
+			t.Error(v, d)
+		}
+	})
+}
+
+func FuzzFP3FromDecimal(f *testing.F) {
+	tests := []string{"0", "1.5", "-1.5e3", ".5", "1e-4", "1..2", "e", "1e"}
+	for _, tc := range tests {
+		f.Add(tc)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		v, err := protoconv.FP3FromDecimal(s)
+		if err != nil {
+			if v != fp3.Zero {
+				t.Error("has to be 0 on error")
+			}
+			return
+		}
+
+		// lossless, so printed value parses back to same value
+		if q, err := protoconv.FP3FromDecimal(v.String()); err != nil || q != v {
+			t.Error(s, v, q, err)
+		}
+	})
+}
+
+func ExampleFP3ToMoney() {
+	d, _ := fp3.FromString("-12.5")
+	units, nanos := protoconv.FP3ToMoney(d)
+	fmt.Println(units, nanos)
+	// Output: -12 -500000000
+}
+
+func ExampleFP3FromMoney() {
+	d, err := protoconv.FP3FromMoney(12, 345_000_000)
+	fmt.Println(d, err)
+
+	_, err = protoconv.FP3FromMoney(12, 345_600_000)
+	fmt.Println(err)
+	// Output:
+	// 12.345 <nil>
+	// more fraction digits than supported
+}
+
+func ExampleFP6FromDecimal() {
+	d, err := protoconv.FP6FromDecimal("1.25e-3")
+	fmt.Println(d, err)
+	// Output: 0.00125 <nil>
+}
@@ -0,0 +1,187 @@
+package fpdecimal
+
+import (
+	"math/bits"
+	"strconv"
+)
+
+// RoundingMode is how discarded fractions are rounded, as in math/big.
+type RoundingMode byte
+
+// These constants define supported rounding modes.
+const (
+	ToNearestEven RoundingMode = iota // == IEEE 754-2008 roundTiesToEven
+	ToNearestAway                     // == IEEE 754-2008 roundTiesToAway
+	ToZero                            // == IEEE 754-2008 roundTowardZero
+	AwayFromZero                      // no IEEE 754-2008 equivalent
+	ToNegativeInf                     // == IEEE 754-2008 roundTowardNegative
+	ToPositiveInf                     // == IEEE 754-2008 roundTowardPositive
+)
+
+func (m RoundingMode) String() string {
+	switch m {
+	case ToNearestEven:
+		return "ToNearestEven"
+	case ToNearestAway:
+		return "ToNearestAway"
+	case ToZero:
+		return "ToZero"
+	case AwayFromZero:
+		return "AwayFromZero"
+	case ToNegativeInf:
+		return "ToNegativeInf"
+	case ToPositiveInf:
+		return "ToPositiveInf"
+	default:
+		return "RoundingMode(" + strconv.Itoa(int(m)) + ")"
+	}
+}
+
+var errDivisionByZero = &errorString{"division by zero"}
+
+// roundUp is true when magnitude of quotient q with remainder r of division by d has to be incremented.
+// Remainder is less than d.
+func (m RoundingMode) roundUp(q, r, d uint64, neg bool) bool {
+	if r == 0 {
+		return false
+	}
+	switch m {
+	case ToNearestEven:
+		return r > d-r || (r == d-r && q&1 == 1)
+	case ToNearestAway:
+		return r >= d-r
+	case AwayFromZero:
+		return true
+	case ToNegativeInf:
+		return neg
+	case ToPositiveInf:
+		return !neg
+	default:
+		return false
+	}
+}
+
+// DivRound returns a/b rounded by mode.
+// Panics when b is zero, as integer division.
+func DivRound(a, b int64, mode RoundingMode) int64 {
+	q, r := a/b, a%b
+	if r == 0 {
+		return q
+	}
+
+	neg := (a < 0) != (b < 0)
+	if !mode.roundUp(abs(q), abs(r), abs(b), neg) {
+		return q
+	}
+	if neg {
+		return q - 1
+	}
+	return q + 1
+}
+
+// DivRoundUint is DivRound of unsigned integers.
+func DivRoundUint(a, b uint64, mode RoundingMode) uint64 {
+	q, r := a/b, a%b
+	if mode.roundUp(q, r, b, false) {
+		q++
+	}
+	return q
+}
+
+// MulDivRound returns a×b/c rounded by mode.
+// Product is 128 bits, so it does not overflow unless result does not fit int64.
+func MulDivRound(a, b, c int64, mode RoundingMode) (int64, error) {
+	if c == 0 {
+		return 0, errDivisionByZero
+	}
+
+	return mulDivRound(abs(a), abs(b), abs(c), (a < 0) != (b < 0) != (c < 0), mode)
+}
+
+// MulDivRoundUint is MulDivRound of unsigned integers.
+func MulDivRoundUint(a, b, c uint64, mode RoundingMode) (uint64, error) {
+	if c == 0 {
+		return 0, errDivisionByZero
+	}
+
+	return mulDivRoundUint(a, b, c, false, mode)
+}
+
+// AddUint returns a+b, overflow is error.
+func AddUint(a, b uint64) (uint64, error) {
+	v, carry := bits.Add64(a, b, 0)
+	if carry != 0 {
+		return 0, errOverflow
+	}
+	return v, nil
+}
+
+// SubUint returns a-b, negative result is error.
+func SubUint(a, b uint64) (uint64, error) {
+	if a < b {
+		return unsigned(b-a, true)
+	}
+	return a - b, nil
+}
+
+// FixedPointDecimalToUint converts int64 to uint64, such as decoded signed value, negative value is error.
+func FixedPointDecimalToUint(v int64) (uint64, error) { return unsigned(abs(v), v < 0) }
+
+// FixedPointDecimalFromUint converts uint64 to int64, such as for encoding as signed value, value beyond int64 is error.
+func FixedPointDecimalFromUint(v uint64) (int64, error) { return signed(v, false) }
+
+// mulDivRound returns a×b/c of magnitudes a, b, c and sign neg, rounded by mode.
+func mulDivRound(ua, ub, uc uint64, neg bool, mode RoundingMode) (int64, error) {
+	q, err := mulDivRoundUint(ua, ub, uc, neg, mode)
+	if err != nil {
+		return 0, err
+	}
+	return signed(q, neg)
+}
+
+// mulDivRoundUint returns magnitude of a×b/c, rounded by mode as of sign neg.
+func mulDivRoundUint(ua, ub, uc uint64, neg bool, mode RoundingMode) (uint64, error) {
+	hi, lo := bits.Mul64(ua, ub)
+	if hi >= uc {
+		return 0, errOverflow
+	}
+	q, r := bits.Div64(hi, lo, uc)
+
+	if mode.roundUp(q, r, uc, neg) {
+		q++
+		if q == 0 {
+			return 0, errOverflow
+		}
+	}
+
+	return q, nil
+}
+
+func abs(v int64) uint64 {
+	if v < 0 {
+		return -uint64(v)
+	}
+	return uint64(v)
+}
+
+// signed converts magnitude and sign to int64.
+func signed(u uint64, neg bool) (int64, error) {
+	if neg {
+		if u > 1<<63 {
+			return 0, errOverflow
+		}
+		return int64(-u), nil
+	}
+	if u > 1<<63-1 {
+		return 0, errOverflow
+	}
+	return int64(u), nil
+}
+
+// unsigned converts magnitude and sign to uint64, negative value is error.
+func unsigned(u uint64, neg bool) (uint64, error) {
+	if neg && u != 0 {
+		return 0, errNegative
+	}
+	return u, nil
+}
@@ -0,0 +1,384 @@
+package fpdecimal_test
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/nikolaydubina/fpdecimal"
+)
+
+var roundingModes = []fpdecimal.RoundingMode{
+	fpdecimal.ToNearestEven,
+	fpdecimal.ToNearestAway,
+	fpdecimal.ToZero,
+	fpdecimal.AwayFromZero,
+	fpdecimal.ToNegativeInf,
+	fpdecimal.ToPositiveInf,
+}
+
+func TestDivRound(t *testing.T) {
+	// a/10 for each mode, in order of roundingModes
+	tests := []struct {
+		a  int64
+		vs [6]int64
+	}{
+		{0, [6]int64{0, 0, 0, 0, 0, 0}},
+		{10, [6]int64{1, 1, 1, 1, 1, 1}},
+		{11, [6]int64{1, 1, 1, 2, 1, 2}},
+		{15, [6]int64{2, 2, 1, 2, 1, 2}},
+		{25, [6]int64{2, 3, 2, 3, 2, 3}},
+		{19, [6]int64{2, 2, 1, 2, 1, 2}},
+		{-11, [6]int64{-1, -1, -1, -2, -2, -1}},
+		{-15, [6]int64{-2, -2, -1, -2, -2, -1}},
+		{-25, [6]int64{-2, -3, -2, -3, -3, -2}},
+		{-19, [6]int64{-2, -2, -1, -2, -2, -1}},
+	}
+	for _, tc := range tests {
+		for i, mode := range roundingModes {
+			t.Run(fmt.Sprint(tc.a, mode), func(t *testing.T) {
+				if v := fpdecimal.DivRound(tc.a, 10, mode); v != tc.vs[i] {
+					t.Error(v, tc.vs[i])
+				}
+				if v := fpdecimal.DivRound(-tc.a, -10, mode); v != tc.vs[i] {
+					t.Error(v, tc.vs[i])
+				}
+				if v, err := fpdecimal.MulDivRound(tc.a, 1, 10, mode); err != nil || v != tc.vs[i] {
+					t.Error(v, tc.vs[i], err)
+				}
+			})
+		}
+	}
+}
+
+func TestMulDivRound(t *testing.T) {
+	tests := []struct {
+		a, b, c int64
+		mode    fpdecimal.RoundingMode
+		v       int64
+	}{
+		{math.MaxInt64, math.MaxInt64, math.MaxInt64, fpdecimal.ToZero, math.MaxInt64},
+		{math.MinInt64, math.MaxInt64, math.MaxInt64, fpdecimal.ToZero, math.MinInt64},
+		{100_500, 1_000_001, 1_000_000, fpdecimal.ToNearestEven, 100_500},
+		{100_500, 1_999_999, 1_000_000, fpdecimal.ToNearestEven, 201_000},
+		{100_500, 1_999_999, 1_000_000, fpdecimal.ToZero, 200_999},
+		{-7, 1, 2, fpdecimal.ToNearestEven, -4},
+		{-5, 1, 2, fpdecimal.ToNearestEven, -2},
+	}
+	for _, tc := range tests {
+		t.Run(fmt.Sprint(tc), func(t *testing.T) {
+			v, err := fpdecimal.MulDivRound(tc.a, tc.b, tc.c, tc.mode)
+			if err != nil {
+				t.Error(err)
+			}
+			if v != tc.v {
+				t.Error(v, tc.v)
+			}
+		})
+	}
+}
+
+func TestMulDivRound_Error(t *testing.T) {
+	tests := []struct {
+		a, b, c int64
+		err     string
+	}{
+		{1, 1, 0, "division by zero"},
+		{math.MaxInt64, 2, 1, "overflow"},
+		{math.MinInt64, 1, -1, "overflow"},
+		{math.MaxInt64, math.MaxInt64, 1, "overflow"},
+	}
+	for _, tc := range tests {
+		t.Run(fmt.Sprint(tc), func(t *testing.T) {
+			v, err := fpdecimal.MulDivRound(tc.a, tc.b, tc.c, fpdecimal.ToZero)
+			if err == nil || err.Error() != tc.err {
+				t.Error(err)
+			}
+			if v != 0 {
+				t.Error(v)
+			}
+		})
+	}
+}
+
+func FuzzMulDivRound(f *testing.F) {
+	f.Add(int64(100_500), int64(1_999_999), int64(1_000_000), uint8(0))
+	f.Add(int64(-7), int64(1), int64(2), uint8(1))
+	f.Add(int64(math.MinInt64), int64(math.MaxInt64), int64(3), uint8(4))
+	f.Fuzz(func(t *testing.T, a, b, c int64, m uint8) {
+		if c == 0 || int(m) >= len(roundingModes) {
+			t.Skip()
+		}
+		mode := roundingModes[m]
+
+		// math/big has no rounding modes for integers, so rounding is done here on exact rational
+		r := new(big.Rat).SetFrac(new(big.Int).Mul(big.NewInt(a), big.NewInt(b)), big.NewInt(c))
+		q, rem := new(big.Int).QuoRem(r.Num(), r.Denom(), new(big.Int))
+		if rem.Sign() != 0 {
+			half := new(big.Int).Mul(new(big.Int).Abs(rem), big.NewInt(2)).Cmp(r.Denom())
+			up := false
+			switch mode {
+			case fpdecimal.ToNearestEven:
+				up = half > 0 || (half == 0 && q.Bit(0) == 1)
+			case fpdecimal.ToNearestAway:
+				up = half >= 0
+			case fpdecimal.AwayFromZero:
+				up = true
+			case fpdecimal.ToNegativeInf:
+				up = r.Sign() < 0
+			case fpdecimal.ToPositiveInf:
+				up = r.Sign() > 0
+			}
+			if up {
+				q.Add(q, big.NewInt(int64(r.Sign())))
+			}
+		}
+
+		v, err := fpdecimal.MulDivRound(a, b, c, mode)
+		if !q.IsInt64() {
+			if err == nil {
+				t.Error("expected overflow", a, b, c, mode, v)
+			}
+			return
+		}
+		if err != nil || v != q.Int64() {
+			t.Error(a, b, c, mode, v, q, err)
+		}
+
+		if b == 1 && !(a == math.MinInt64 && c == -1) {
+			if v := fpdecimal.DivRound(a, c, mode); v != q.Int64() {
+				t.Error(a, c, mode, v, q)
+			}
+		}
+	})
+}
+
+func TestRoundingMode_String(t *testing.T) {
+	for _, mode := range roundingModes {
+		if s := mode.String(); s == "" {
+			t.Error(s)
+		}
+	}
+	if s := fpdecimal.RoundingMode(42).String(); s != "RoundingMode(42)" {
+		t.Error(s)
+	}
+}
+
+func TestDivRoundUint(t *testing.T) {
+	// in order of roundingModes
+	tests := []struct {
+		a, b uint64
+		qs   [6]uint64
+	}{
+		{0, 3, [6]uint64{0, 0, 0, 0, 0, 0}},
+		{6, 3, [6]uint64{2, 2, 2, 2, 2, 2}},
+		{7, 2, [6]uint64{4, 4, 3, 4, 3, 4}},
+		{5, 2, [6]uint64{2, 3, 2, 3, 2, 3}},
+		{math.MaxUint64, 2, [6]uint64{1 << 63, 1 << 63, 1<<63 - 1, 1 << 63, 1<<63 - 1, 1 << 63}},
+		{math.MaxUint64, math.MaxUint64 - 1, [6]uint64{1, 1, 1, 2, 1, 2}},
+	}
+	for _, tc := range tests {
+		for i, mode := range roundingModes {
+			t.Run(fmt.Sprint(tc.a, tc.b, mode), func(t *testing.T) {
+				if q := fpdecimal.DivRoundUint(tc.a, tc.b, mode); q != tc.qs[i] {
+					t.Error(q, tc.qs[i])
+				}
+			})
+		}
+	}
+}
+
+func TestDivRoundWide(t *testing.T) {
+	// in order of roundingModes
+	tests := []struct {
+		hi int64
+		lo uint64
+		d  uint64
+		qs [6]string
+	}{
+		{0, 0, 3, [6]string{"0", "0", "0", "0", "0", "0"}},
+		{0, 25, 10, [6]string{"2", "3", "2", "3", "2", "3"}},
+		{-1, math.MaxUint64 - 24, 10, [6]string{"-2", "-3", "-2", "-3", "-3", "-2"}},
+		{1, 5, 10, [6]string{"1844674407370955162", "1844674407370955162", "1844674407370955162", "1844674407370955163", "1844674407370955162", "1844674407370955163"}},
+		{math.MaxInt64, math.MaxUint64, 1, [6]string{"170141183460469231731687303715884105727", "170141183460469231731687303715884105727", "170141183460469231731687303715884105727", "170141183460469231731687303715884105727", "170141183460469231731687303715884105727", "170141183460469231731687303715884105727"}},
+		{math.MinInt64, 0, 1, [6]string{"-170141183460469231731687303715884105728", "-170141183460469231731687303715884105728", "-170141183460469231731687303715884105728", "-170141183460469231731687303715884105728", "-170141183460469231731687303715884105728", "-170141183460469231731687303715884105728"}},
+		{math.MinInt64, 0, 3, [6]string{"-56713727820156410577229101238628035243", "-56713727820156410577229101238628035243", "-56713727820156410577229101238628035242", "-56713727820156410577229101238628035243", "-56713727820156410577229101238628035243", "-56713727820156410577229101238628035242"}},
+	}
+	for _, tc := range tests {
+		for i, mode := range roundingModes {
+			t.Run(fmt.Sprint(tc.hi, tc.lo, tc.d, mode), func(t *testing.T) {
+				if hi, lo := fpdecimal.DivRoundWide(tc.hi, tc.lo, tc.d, mode); fpdecimal.FixedPointDecimalWideToString(hi, lo, 0) != tc.qs[i] {
+					t.Error(hi, lo, tc.qs[i])
+				}
+			})
+		}
+	}
+}
+
+func TestMulDivRoundWide(t *testing.T) {
+	tests := []struct {
+		a, b, c string
+		mode    fpdecimal.RoundingMode
+		q       string
+	}{
+		{"25", "1", "10", fpdecimal.ToNearestEven, "2"},
+		{"-25", "1", "10", fpdecimal.ToNearestAway, "-3"},
+		{"25", "-1", "-10", fpdecimal.ToZero, "2"},
+		{"18446744073709551616", "18446744073709551616", "18446744073709551617", fpdecimal.ToZero, "18446744073709551615"},
+		{"18446744073709551616", "18446744073709551616", "18446744073709551617", fpdecimal.ToPositiveInf, "18446744073709551616"},
+		{"170141183460469231731687303715884105727", "170141183460469231731687303715884105727", "170141183460469231731687303715884105727", fpdecimal.ToZero, "170141183460469231731687303715884105727"},
+		{"-170141183460469231731687303715884105728", "1", "1", fpdecimal.ToZero, "-170141183460469231731687303715884105728"},
+		{"10000000000000000000000000000000000000", "3", "90000000000000000000000000000000000000", fpdecimal.ToNearestEven, "0"},
+		{"10000000000000000000000000000000000000", "5", "100000000000000000000000000000000000000", fpdecimal.ToNearestEven, "0"},
+		{"10000000000000000000000000000000000000", "15", "100000000000000000000000000000000000000", fpdecimal.ToNearestEven, "2"},
+	}
+	for _, tc := range tests {
+		t.Run(fmt.Sprint(tc.a, tc.b, tc.c, tc.mode), func(t *testing.T) {
+			ahi, alo, _ := fpdecimal.ParseFixedPointDecimalWide([]byte(tc.a), 0)
+			bhi, blo, _ := fpdecimal.ParseFixedPointDecimalWide([]byte(tc.b), 0)
+			chi, clo, _ := fpdecimal.ParseFixedPointDecimalWide([]byte(tc.c), 0)
+			hi, lo, err := fpdecimal.MulDivRoundWide(ahi, alo, bhi, blo, chi, clo, tc.mode)
+			if err != nil || fpdecimal.FixedPointDecimalWideToString(hi, lo, 0) != tc.q {
+				t.Error(hi, lo, err, tc.q)
+			}
+		})
+	}
+
+	errs := []struct {
+		a, b, c string
+		err     string
+	}{
+		{"1", "1", "0", "division by zero"},
+		{"170141183460469231731687303715884105727", "2", "1", "overflow"},
+		{"-170141183460469231731687303715884105728", "-1", "1", "overflow"},
+	}
+	for _, tc := range errs {
+		t.Run(fmt.Sprint(tc.a, tc.b, tc.c), func(t *testing.T) {
+			ahi, alo, _ := fpdecimal.ParseFixedPointDecimalWide([]byte(tc.a), 0)
+			bhi, blo, _ := fpdecimal.ParseFixedPointDecimalWide([]byte(tc.b), 0)
+			chi, clo, _ := fpdecimal.ParseFixedPointDecimalWide([]byte(tc.c), 0)
+			if hi, lo, err := fpdecimal.MulDivRoundWide(ahi, alo, bhi, blo, chi, clo, fpdecimal.ToZero); err == nil || err.Error() != tc.err || hi != 0 || lo != 0 {
+				t.Error(hi, lo, err)
+			}
+		})
+	}
+}
+
+func FuzzMulDivRoundWide(f *testing.F) {
+	f.Add(int64(0), uint64(25), int64(0), uint64(1), int64(0), uint64(10), uint8(0))
+	f.Add(int64(math.MaxInt64), uint64(math.MaxUint64), int64(math.MaxInt64), uint64(math.MaxUint64), int64(math.MaxInt64), uint64(3), uint8(1))
+	f.Add(int64(-1), uint64(1), int64(1), uint64(0), int64(-5), uint64(7), uint8(4))
+	f.Add(int64(math.MinInt64), uint64(0), int64(0), uint64(3), int64(0), uint64(7), uint8(5))
+	f.Fuzz(func(t *testing.T, ahi int64, alo uint64, bhi int64, blo uint64, chi int64, clo uint64, m uint8) {
+		if int(m) >= len(roundingModes) || (chi == 0 && clo == 0) {
+			t.Skip()
+		}
+		mode := roundingModes[m]
+
+		hi, lo, err := fpdecimal.MulDivRoundWide(ahi, alo, bhi, blo, chi, clo, mode)
+
+		r := new(big.Rat).SetFrac(new(big.Int).Mul(wideBig(ahi, alo), wideBig(bhi, blo)), wideBig(chi, clo))
+		qhi, qlo, qerr := fpdecimal.BigRatToFixedPointDecimalWide(r, 0, mode)
+		if (err != nil) != (qerr != nil) || hi != qhi || lo != qlo {
+			t.Error(hi, lo, err, qhi, qlo, qerr)
+		}
+	})
+}
+
+func TestFixedPointDecimalFromWide(t *testing.T) {
+	tests := []struct {
+		hi   int64
+		lo   uint64
+		p, q uint8
+		mode fpdecimal.RoundingMode
+		v    int64
+	}{
+		{0, 1_500_000_000_000_000_000, 18, 0, fpdecimal.ToNearestEven, 2},
+		{0, 1_500_000_000_000_000_000, 18, 0, fpdecimal.ToZero, 1},
+		{-1, math.MaxUint64 - 1_499_999_999_999_999_999, 18, 0, fpdecimal.ToNearestAway, -2},
+		{0, 1_234_567_000_000_000_000, 18, 6, fpdecimal.ToZero, 1_234_567},
+		{0, 1234, 3, 3, fpdecimal.ToZero, 1234},
+		{math.MaxInt64, math.MaxUint64, 38, 0, fpdecimal.ToNearestEven, 2},
+		{0x1ed09bead87c0, 0x378d8e63ffffffff, 19, 3, fpdecimal.ToZero, 999_999_999_999_999_999},
+	}
+	for _, tc := range tests {
+		t.Run(fmt.Sprint(tc), func(t *testing.T) {
+			if v, err := fpdecimal.FixedPointDecimalFromWide(tc.hi, tc.lo, tc.p, tc.q, tc.mode); err != nil || v != tc.v {
+				t.Error(v, tc.v, err)
+			}
+		})
+	}
+
+	if v, err := fpdecimal.FixedPointDecimalFromWide(1, 0, 18, 6, fpdecimal.ToZero); err != nil || v != 18_446_744 {
+		t.Error(v, err)
+	}
+	if v, err := fpdecimal.FixedPointDecimalFromWide(1, 0, 3, 3, fpdecimal.ToZero); err == nil || err.Error() != "overflow" || v != 0 {
+		t.Error(v, err)
+	}
+}
+
+func FuzzMulDivRoundUint(f *testing.F) {
+	f.Add(uint64(100_500), uint64(1_999_999), uint64(1_000_000), uint8(0))
+	f.Add(uint64(math.MaxUint64), uint64(math.MaxUint64), uint64(math.MaxUint64), uint8(3))
+	f.Add(uint64(math.MaxUint64), uint64(1), uint64(2), uint8(1))
+	f.Fuzz(func(t *testing.T, a, b, c uint64, m uint8) {
+		if int(m) >= len(roundingModes) {
+			t.Skip()
+		}
+		mode := roundingModes[m]
+
+		q, err := fpdecimal.MulDivRoundUint(a, b, c, mode)
+		if c == 0 {
+			if err == nil || q != 0 {
+				t.Error(a, b, c, q, err)
+			}
+			return
+		}
+
+		// exact product is rounded by BigRatToFixedPointDecimalUint
+		r := new(big.Rat).SetFrac(new(big.Int).Mul(new(big.Int).SetUint64(a), new(big.Int).SetUint64(b)), new(big.Int).SetUint64(c))
+		e, eerr := fpdecimal.BigRatToFixedPointDecimalUint(r, 0, mode)
+		if (err == nil) != (eerr == nil) || q != e {
+			t.Error(a, b, c, mode, q, e, err, eerr)
+		}
+
+		// same as signed, when fits
+		if a <= math.MaxInt64 && b <= math.MaxInt64 && c <= math.MaxInt64 {
+			s, serr := fpdecimal.MulDivRound(int64(a), int64(b), int64(c), mode)
+			if serr == nil && (err != nil || q != uint64(s)) {
+				t.Error(a, b, c, mode, q, s, err)
+			}
+		}
+	})
+}
+
+func TestAddSubUint(t *testing.T) {
+	if v, err := fpdecimal.AddUint(math.MaxUint64-1, 1); err != nil || v != math.MaxUint64 {
+		t.Error(v, err)
+	}
+	if v, err := fpdecimal.AddUint(math.MaxUint64, 1); err == nil || err.Error() != "overflow" || v != 0 {
+		t.Error(v, err)
+	}
+	if v, err := fpdecimal.SubUint(5, 5); err != nil || v != 0 {
+		t.Error(v, err)
+	}
+	if v, err := fpdecimal.SubUint(4, 5); err == nil || err.Error() != "negative value" || v != 0 {
+		t.Error(v, err)
+	}
+}
+
+func TestFixedPointDecimalUint(t *testing.T) {
+	if v, err := fpdecimal.FixedPointDecimalToUint(math.MaxInt64); err != nil || v != math.MaxInt64 {
+		t.Error(v, err)
+	}
+	if v, err := fpdecimal.FixedPointDecimalToUint(-1); err == nil || err.Error() != "negative value" || v != 0 {
+		t.Error(v, err)
+	}
+	if v, err := fpdecimal.FixedPointDecimalFromUint(math.MaxInt64); err != nil || v != math.MaxInt64 {
+		t.Error(v, err)
+	}
+	if v, err := fpdecimal.FixedPointDecimalFromUint(math.MaxInt64 + 1); err == nil || err.Error() != "overflow" || v != 0 {
+		t.Error(v, err)
+	}
+}
@@ -0,0 +1,79 @@
+package fpdecimal
+
+import "math"
+
+var (
+	errOverflow  = &errorString{"overflow"}
+	errPrecision = &errorString{"more fraction digits than supported"}
+)
+
+var pow10 = [...]int64{
+	1,
+	10,
+	100,
+	1_000,
+	10_000,
+	100_000,
+	1_000_000,
+	10_000_000,
+	100_000_000,
+	1_000_000_000,
+	10_000_000_000,
+	100_000_000_000,
+	1_000_000_000_000,
+	10_000_000_000_000,
+	100_000_000_000_000,
+	1_000_000_000_000_000,
+	10_000_000_000_000_000,
+	100_000_000_000_000_000,
+	1_000_000_000_000_000_000,
+}
+
+// pow10Uint returns 10^e for e from 0 to len(pow10), since 10^19 fits uint64 but not int64.
+func pow10Uint(e int) uint64 {
+	if e == len(pow10) {
+		return uint64(pow10[len(pow10)-1]) * 10
+	}
+	return uint64(pow10[e])
+}
+
+// scaleExp converts m×10^e into fixed-point decimal of p fractions.
+// Fractions are never discarded, instead error is returned.
+func scaleExp(m int64, e int, p uint8) (int64, error) {
+	u, err := scaleExpUint(abs(m), e, p)
+	if err != nil {
+		return 0, err
+	}
+	return signed(u, m < 0)
+}
+
+// scaleExpUint is scaleExp of magnitude.
+func scaleExpUint(m uint64, e int, p uint8) (uint64, error) {
+	if m == 0 {
+		return 0, nil
+	}
+
+	e += int(p)
+	switch {
+	case e == 0:
+		return m, nil
+	case e < 0:
+		if e < -len(pow10) {
+			return 0, errPrecision
+		}
+		d := pow10Uint(-e)
+		if m%d != 0 {
+			return 0, errPrecision
+		}
+		return m / d, nil
+	default:
+		if e > len(pow10) {
+			return 0, errOverflow
+		}
+		d := pow10Uint(e)
+		if m > math.MaxUint64/d {
+			return 0, errOverflow
+		}
+		return m * d, nil
+	}
+}
@@ -0,0 +1,4 @@
+// Package ufp6 is unsigned fixed-point decimal of 6 fractional digits, for values that can never be negative.
+// Constructors return error instead of negative value.
+// Arithmetics wraps around as uint64, Checked variants return error instead of negative value or overflow.
+package ufp6
@@ -0,0 +1,202 @@
+package fpdecimal
+
+import "math/bits"
+
+var (
+	errBadPrecision      = &errorString{"precision has to be positive and not less than scale"}
+	errExceedsPrecision  = &errorString{"more digits than precision"}
+	errTwosComplementLen = &errorString{"value does not fit into bytes"}
+	errEmptyBytes        = &errorString{"empty bytes"}
+)
+
+// FixedPointDecimalToUnscaled converts fixed-point decimal of p fractions to unscaled value of DECIMAL(precision, scale).
+// This is representation of decimals in Apache Arrow, Parquet, Avro, and SQL databases.
+// Returns error when value has more fractions than scale or more digits than precision.
+func FixedPointDecimalToUnscaled(v int64, p uint8, precision, scale uint8) (int64, error) {
+	if err := checkPrecision(0, precision, scale); err != nil {
+		return 0, err
+	}
+	u, err := scaleExp(v, -int(p), scale)
+	if err != nil {
+		return 0, err
+	}
+	if err := checkPrecision(u, precision, scale); err != nil {
+		return 0, err
+	}
+	return u, nil
+}
+
+// FixedPointDecimalFromUnscaled converts unscaled value of DECIMAL(precision, scale) to fixed-point decimal of p fractions.
+// Returns error when value has more fractions than p or more digits than precision.
+func FixedPointDecimalFromUnscaled(u int64, precision, scale uint8, p uint8) (int64, error) {
+	if err := checkPrecision(u, precision, scale); err != nil {
+		return 0, err
+	}
+	return scaleExp(u, -int(scale), p)
+}
+
+func checkPrecision(u int64, precision, scale uint8) error {
+	if precision == 0 || scale > precision {
+		return errBadPrecision
+	}
+	if int(precision) < len(pow10) && (u >= pow10[precision] || u <= -pow10[precision]) {
+		return errExceedsPrecision
+	}
+	return nil
+}
+
+// FixedPointDecimalWideToUnscaled is FixedPointDecimalToUnscaled for 128 bits, such as Arrow Decimal128 and Parquet precision up to 38.
+// Values are two's complement of high and low bits.
+func FixedPointDecimalWideToUnscaled(hi int64, lo uint64, p uint8, precision, scale uint8) (uhi int64, ulo uint64, err error) {
+	if err := checkPrecisionWide(0, 0, precision, scale); err != nil {
+		return 0, 0, err
+	}
+	mhi, mlo, neg := absWide(hi, lo)
+	mhi, mlo, err = scaleExpWide(mhi, mlo, -int(p), scale)
+	if err != nil {
+		return 0, 0, err
+	}
+	if err := checkPrecisionWide(mhi, mlo, precision, scale); err != nil {
+		return 0, 0, err
+	}
+	return signedWide(mhi, mlo, neg)
+}
+
+// FixedPointDecimalWideFromUnscaled is FixedPointDecimalFromUnscaled for 128 bits.
+// Values are two's complement of high and low bits.
+func FixedPointDecimalWideFromUnscaled(uhi int64, ulo uint64, precision, scale uint8, p uint8) (hi int64, lo uint64, err error) {
+	mhi, mlo, neg := absWide(uhi, ulo)
+	if err := checkPrecisionWide(mhi, mlo, precision, scale); err != nil {
+		return 0, 0, err
+	}
+	mhi, mlo, err = scaleExpWide(mhi, mlo, -int(scale), p)
+	if err != nil {
+		return 0, 0, err
+	}
+	return signedWide(mhi, mlo, neg)
+}
+
+// checkPrecisionWide is checkPrecision of 128-bit magnitude.
+func checkPrecisionWide(hi, lo uint64, precision, scale uint8) error {
+	if precision == 0 || scale > precision {
+		return errBadPrecision
+	}
+	if precision <= 38 {
+		if phi, plo := pow10Wide(int(precision)); !lessWide(hi, lo, phi, plo) {
+			return errExceedsPrecision
+		}
+	}
+	return nil
+}
+
+// AppendTwosComplement appends big-endian two's complement of v in size bytes.
+// This is encoding of unscaled decimals in Parquet FIXED_LEN_BYTE_ARRAY and BYTE_ARRAY, and Avro bytes and fixed.
+// When size is 0, minimal number of bytes is used.
+// Returns error when v does not fit into size bytes.
+func AppendTwosComplement(b []byte, v int64, size int) ([]byte, error) {
+	n := 1
+	for ; n < 8; n++ {
+		if s := v >> (8*n - 1); s == 0 || s == -1 {
+			break
+		}
+	}
+	if size == 0 {
+		size = n
+	}
+	if size < n {
+		return b, errTwosComplementLen
+	}
+
+	ext := byte(0)
+	if v < 0 {
+		ext = 0xff
+	}
+	for i := size; i > n; i-- {
+		b = append(b, ext)
+	}
+	for i := n - 1; i >= 0; i-- {
+		b = append(b, byte(v>>(8*i)))
+	}
+	return b, nil
+}
+
+// ParseTwosComplement parses big-endian two's complement of any size.
+// Returns error when value does not fit int64.
+func ParseTwosComplement(b []byte) (int64, error) {
+	if len(b) == 0 {
+		return 0, errEmptyBytes
+	}
+
+	// skip sign extension
+	for len(b) > 8 {
+		if (b[0] != 0 || b[1]&0x80 != 0) && (b[0] != 0xff || b[1]&0x80 == 0) {
+			return 0, errOverflow
+		}
+		b = b[1:]
+	}
+
+	v := int64(int8(b[0]))
+	for _, q := range b[1:] {
+		v = v<<8 | int64(q)
+	}
+	return v, nil
+}
+
+// AppendTwosComplementWide is AppendTwosComplement for two's complement of high and low bits.
+func AppendTwosComplementWide(b []byte, hi int64, lo uint64, size int) ([]byte, error) {
+	// bits of magnitude and sign, of bitwise not when negative
+	x, y := uint64(hi), lo
+	if hi < 0 {
+		x, y = ^x, ^y
+	}
+	lz := bits.LeadingZeros64(x)
+	if x == 0 {
+		lz += bits.LeadingZeros64(y)
+	}
+	n := (128 - lz + 1 + 7) / 8
+
+	if size == 0 {
+		size = n
+	}
+	if size < n {
+		return b, errTwosComplementLen
+	}
+
+	ext := byte(0)
+	if hi < 0 {
+		ext = 0xff
+	}
+	for i := size; i > n; i-- {
+		b = append(b, ext)
+	}
+	for i := n - 1; i >= 8; i-- {
+		b = append(b, byte(uint64(hi)>>(8*(i-8))))
+	}
+	for i := min(n, 8) - 1; i >= 0; i-- {
+		b = append(b, byte(lo>>(8*i)))
+	}
+	return b, nil
+}
+
+// ParseTwosComplementWide is ParseTwosComplement for two's complement of high and low bits.
+// Returns error when value does not fit 128 bits.
+func ParseTwosComplementWide(b []byte) (hi int64, lo uint64, err error) {
+	if len(b) == 0 {
+		return 0, 0, errEmptyBytes
+	}
+
+	// skip sign extension
+	for len(b) > 16 {
+		if (b[0] != 0 || b[1]&0x80 != 0) && (b[0] != 0xff || b[1]&0x80 == 0) {
+			return 0, 0, errOverflow
+		}
+		b = b[1:]
+	}
+
+	u := uint64(int64(int8(b[0])) >> 7)
+	lo = u
+	for _, q := range b {
+		u, lo = u<<8|lo>>56, lo<<8|uint64(q)
+	}
+	return int64(u), lo, nil
+}
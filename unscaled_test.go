@@ -0,0 +1,308 @@
+package fpdecimal_test
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/nikolaydubina/fpdecimal"
+)
+
+func TestFixedPointDecimalToUnscaled(t *testing.T) {
+	tests := []struct {
+		v         int64
+		p         uint8
+		precision uint8
+		scale     uint8
+		u         int64
+	}{
+		{12345, 3, 18, 3, 12345},
+		{12345, 3, 18, 5, 1234500},
+		{12340, 3, 18, 2, 1234},
+		{12000, 3, 2, 0, 12},
+		{-12345, 3, 5, 3, -12345},
+		{0, 3, 1, 0, 0},
+		{math.MaxInt64, 3, 38, 3, math.MaxInt64},
+		{math.MinInt64, 3, 19, 3, math.MinInt64},
+	}
+	for _, tc := range tests {
+		t.Run(fmt.Sprint(tc), func(t *testing.T) {
+			u, err := fpdecimal.FixedPointDecimalToUnscaled(tc.v, tc.p, tc.precision, tc.scale)
+			if err != nil {
+				t.Error(err)
+			}
+			if u != tc.u {
+				t.Error(u, tc.u)
+			}
+
+			v, err := fpdecimal.FixedPointDecimalFromUnscaled(u, tc.precision, tc.scale, tc.p)
+			if err != nil {
+				t.Error(err)
+			}
+			if v != tc.v {
+				t.Error(v, tc.v)
+			}
+		})
+	}
+}
+
+func TestFixedPointDecimalToUnscaled_Error(t *testing.T) {
+	tests := []struct {
+		v         int64
+		precision uint8
+		scale     uint8
+		err       string
+	}{
+		{12345, 18, 2, "more fraction digits than supported"},
+		{12345, 4, 3, "more digits than precision"},
+		{-12345, 4, 3, "more digits than precision"},
+		{12345, 0, 0, "precision has to be positive and not less than scale"},
+		{12345, 3, 4, "precision has to be positive and not less than scale"},
+		{math.MaxInt64, 18, 6, "overflow"},
+	}
+	for _, tc := range tests {
+		t.Run(fmt.Sprint(tc), func(t *testing.T) {
+			u, err := fpdecimal.FixedPointDecimalToUnscaled(tc.v, 3, tc.precision, tc.scale)
+			if err == nil || err.Error() != tc.err {
+				t.Error(err)
+			}
+			if u != 0 {
+				t.Error(u)
+			}
+		})
+	}
+}
+
+func TestFixedPointDecimalFromUnscaled_Error(t *testing.T) {
+	tests := []struct {
+		u         int64
+		precision uint8
+		scale     uint8
+		p         uint8
+		err       string
+	}{
+		{12345, 18, 4, 3, "more fraction digits than supported"},
+		{12345, 4, 2, 3, "more digits than precision"},
+		{12345, 18, 0, 16, "overflow"},
+		{1, 2, 3, 3, "precision has to be positive and not less than scale"},
+	}
+	for _, tc := range tests {
+		t.Run(fmt.Sprint(tc), func(t *testing.T) {
+			v, err := fpdecimal.FixedPointDecimalFromUnscaled(tc.u, tc.precision, tc.scale, tc.p)
+			if err == nil || err.Error() != tc.err {
+				t.Error(err)
+			}
+			if v != 0 {
+				t.Error(v)
+			}
+		})
+	}
+}
+
+func TestAppendTwosComplement(t *testing.T) {
+	tests := []struct {
+		v    int64
+		size int
+		b    string
+	}{
+		{0, 0, "00"},
+		{1, 0, "01"},
+		{-1, 0, "ff"},
+		{127, 0, "7f"},
+		{128, 0, "0080"},
+		{-128, 0, "80"},
+		{-129, 0, "ff7f"},
+		{1234500, 0, "12d644"},
+		{-1234500, 0, "ed29bc"},
+		{math.MaxInt64, 0, "7fffffffffffffff"},
+		{math.MinInt64, 0, "8000000000000000"},
+		{1, 4, "00000001"},
+		{-2, 4, "fffffffe"},
+		{-2, 16, "fffffffffffffffffffffffffffffffe"},
+		{1234500, 9, "00000000000012d644"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.b, func(t *testing.T) {
+			b, err := fpdecimal.AppendTwosComplement(nil, tc.v, tc.size)
+			if err != nil {
+				t.Error(err)
+			}
+			if s := hex.EncodeToString(b); s != tc.b {
+				t.Error(s, tc.b)
+			}
+
+			v, err := fpdecimal.ParseTwosComplement(b)
+			if err != nil {
+				t.Error(err)
+			}
+			if v != tc.v {
+				t.Error(v, tc.v)
+			}
+		})
+	}
+}
+
+func TestAppendTwosComplement_Error(t *testing.T) {
+	b, err := fpdecimal.AppendTwosComplement([]byte{1}, 128, 1)
+	if err == nil || err.Error() != "value does not fit into bytes" {
+		t.Error(err)
+	}
+	if len(b) != 1 {
+		t.Error(b)
+	}
+}
+
+func TestParseTwosComplement_Error(t *testing.T) {
+	tests := []struct {
+		b   string
+		err string
+	}{
+		{"", "empty bytes"},
+		{"008000000000000000", "overflow"},
+		{"ff7fffffffffffffff", "overflow"},
+		{"010000000000000000", "overflow"},
+		{"0000000000000000010000000000000000", "overflow"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.b, func(t *testing.T) {
+			b, _ := hex.DecodeString(tc.b)
+			v, err := fpdecimal.ParseTwosComplement(b)
+			if err == nil || err.Error() != tc.err {
+				t.Error(err)
+			}
+			if v != 0 {
+				t.Error(v)
+			}
+		})
+	}
+}
+
+func FuzzTwosComplement(f *testing.F) {
+	tests := []int64{0, 1, 127, 128, 255, 256, math.MaxInt64}
+	for _, tc := range tests {
+		f.Add(tc, 0)
+		f.Add(-tc, 16)
+	}
+	f.Fuzz(func(t *testing.T, v int64, size int) {
+		if size < 0 || size > 32 {
+			t.Skip()
+		}
+		b, err := fpdecimal.AppendTwosComplement(nil, v, size)
+		if err != nil {
+			return
+		}
+		if size > 0 && len(b) != size {
+			t.Error(len(b), size)
+		}
+		if q, err := fpdecimal.ParseTwosComplement(b); err != nil || q != v {
+			t.Error(v, q, err)
+		}
+	})
+}
+
+func TestTwosComplementWide(t *testing.T) {
+	tests := []struct {
+		hi   int64
+		lo   uint64
+		size int
+		b    string
+	}{
+		{0, 0, 0, "00"},
+		{-1, math.MaxUint64, 0, "ff"},
+		{0, 128, 0, "0080"},
+		{0, math.MaxInt64 + 1, 0, "008000000000000000"},
+		{-1, math.MaxInt64 + 1, 0, "8000000000000000"},
+		{-1, math.MaxInt64, 0, "ff7fffffffffffffff"},
+		{1, 0, 0, "010000000000000000"},
+		{math.MaxInt64, math.MaxUint64, 0, "7fffffffffffffffffffffffffffffff"},
+		{math.MinInt64, 0, 0, "80000000000000000000000000000000"},
+		{-1, math.MaxUint64 - 1, 16, "fffffffffffffffffffffffffffffffe"},
+		{1, 0, 17, "0000000000000000010000000000000000"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.b, func(t *testing.T) {
+			b, err := fpdecimal.AppendTwosComplementWide(nil, tc.hi, tc.lo, tc.size)
+			if err != nil {
+				t.Error(err)
+			}
+			if s := hex.EncodeToString(b); s != tc.b {
+				t.Error(s, tc.b)
+			}
+			if hi, lo, err := fpdecimal.ParseTwosComplementWide(b); err != nil || hi != tc.hi || lo != tc.lo {
+				t.Error(hi, lo, err)
+			}
+		})
+	}
+
+	if b, err := fpdecimal.AppendTwosComplementWide(nil, 1, 0, 8); err == nil || err.Error() != "value does not fit into bytes" || len(b) != 0 {
+		t.Error(b, err)
+	}
+
+	errs := []struct {
+		b   string
+		err string
+	}{
+		{"", "empty bytes"},
+		{"0080000000000000000000000000000000", "overflow"},
+		{"ff7fffffffffffffffffffffffffffffff", "overflow"},
+	}
+	for _, tc := range errs {
+		t.Run(tc.b, func(t *testing.T) {
+			b, _ := hex.DecodeString(tc.b)
+			if hi, lo, err := fpdecimal.ParseTwosComplementWide(b); err == nil || err.Error() != tc.err || hi != 0 || lo != 0 {
+				t.Error(hi, lo, err)
+			}
+		})
+	}
+}
+
+func TestFixedPointDecimalWideUnscaled(t *testing.T) {
+	tests := []struct {
+		hi        int64
+		lo        uint64
+		p         uint8
+		precision uint8
+		scale     uint8
+		uhi       int64
+		ulo       uint64
+	}{
+		{0, 12345, 3, 38, 5, 0, 1234500},
+		{-1, math.MaxUint64 - 12344, 3, 5, 3, -1, math.MaxUint64 - 12344},
+		{0x4b3b4ca85a86c47a, 0x098a223fffffffff, 0, 38, 0, 0x4b3b4ca85a86c47a, 0x098a223fffffffff},
+		{math.MaxInt64, math.MaxUint64, 18, 39, 18, math.MaxInt64, math.MaxUint64},
+	}
+	for _, tc := range tests {
+		t.Run(fmt.Sprint(tc), func(t *testing.T) {
+			uhi, ulo, err := fpdecimal.FixedPointDecimalWideToUnscaled(tc.hi, tc.lo, tc.p, tc.precision, tc.scale)
+			if err != nil || uhi != tc.uhi || ulo != tc.ulo {
+				t.Error(uhi, ulo, err)
+			}
+			if hi, lo, err := fpdecimal.FixedPointDecimalWideFromUnscaled(uhi, ulo, tc.precision, tc.scale, tc.p); err != nil || hi != tc.hi || lo != tc.lo {
+				t.Error(hi, lo, err)
+			}
+		})
+	}
+
+	errs := []struct {
+		hi        int64
+		lo        uint64
+		precision uint8
+		scale     uint8
+		err       string
+	}{
+		{0, 12345, 38, 2, "more fraction digits than supported"},
+		{0, 12345, 4, 3, "more digits than precision"},
+		{0x4b3b4ca85a86c47a, 0x098a224000000000, 38, 3, "more digits than precision"},
+		{0, 12345, 3, 4, "precision has to be positive and not less than scale"},
+		{math.MaxInt64, math.MaxUint64, 38, 0, "more fraction digits than supported"},
+		{math.MaxInt64, math.MaxUint64, 39, 39, "overflow"},
+	}
+	for _, tc := range errs {
+		t.Run(fmt.Sprint(tc), func(t *testing.T) {
+			if uhi, ulo, err := fpdecimal.FixedPointDecimalWideToUnscaled(tc.hi, tc.lo, 3, tc.precision, tc.scale); err == nil || err.Error() != tc.err || uhi != 0 || ulo != 0 {
+				t.Error(uhi, ulo, err)
+			}
+		})
+	}
+}